@@ -0,0 +1,81 @@
+package combat
+
+import (
+	"github.com/jaguilar/nh/model/item"
+	"github.com/jaguilar/nh/model/pc"
+)
+
+// Protection is the protection the player has that doesn't come from their
+// equipment. Neither is on the status line.
+type Protection struct {
+	// Divine is the AC bought from priests or granted by prayer.
+	Divine int
+
+	// Spell is the AC from the protection spell, which wears off.
+	Spell int
+}
+
+// AC returns the player's armor class, as nethack's find_ac works it out:
+// their form's base AC (10 for the player races), less each piece of worn
+// armor's AC and enchantment, the enchantment of rings of protection, and
+// prot. Erosion takes away from a piece of armor's AC, but not below 0.
+// Enchantments that aren't known count as 0. So does the base AC of
+// unidentified armor whose possible classes don't agree on it, like the
+// random cloaks; boots, gloves and helmets that aren't identified count as
+// the 1 AC they all give.
+func AC(p *pc.Player, prot Protection) int {
+	ac := 10
+	if p.Species != nil {
+		ac = p.Species.AC
+	}
+	for _, i := range p.Equip {
+		ac -= armorBonus(i)
+	}
+	return ac - prot.Divine - prot.Spell
+}
+
+// armorBonus returns how much i takes away from the AC of the player
+// wearing it.
+func armorBonus(i *item.Item) int {
+	switch {
+	case i == nil:
+		return 0
+	case i.Class.Category == item.Armor:
+		e := erosion(i.Erosion)
+		if e > i.Class.AC {
+			e = i.Class.AC
+		}
+		return i.Class.AC + i.Enhancement.Value - e
+	case i.Class.Name == "ring of protection":
+		return i.Enhancement.Value
+	}
+	return 0
+}
+
+// UnknownEnchantment returns how much more the player's worn items must be
+// enchanted than we know of for AC to give the AC on the status line. It's
+// usually the sum of the enchantments of those items whose enchantments we
+// don't know. If they all are known, a nonzero result means something
+// protects the player that prot leaves out.
+func UnknownEnchantment(p *pc.Player, prot Protection) int {
+	return AC(p, prot) - p.AC
+}
+
+// MC returns the player's magic cancellation: the greatest that any of
+// their worn armor gives. A cloak's MC doesn't hide that of the body armor
+// under it.
+func MC(p *pc.Player) int {
+	mc := 0
+	for _, i := range p.Equip {
+		if i == nil || i.Class.Category != item.Armor {
+			continue
+		}
+		if i.Class.MagicCancellation > mc {
+			mc = i.Class.MagicCancellation
+		}
+	}
+	if mc > 3 {
+		mc = 3
+	}
+	return mc
+}
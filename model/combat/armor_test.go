@@ -0,0 +1,74 @@
+package combat
+
+import (
+	"testing"
+
+	"github.com/jaguilar/nh/model/mon"
+	"github.com/jaguilar/nh/model/pc"
+	"github.com/stretchr/testify/assert"
+)
+
+func player(t *testing.T, equip ...string) *pc.Player {
+	p := &pc.Player{Species: mon.Lookup("human")}
+	for _, e := range equip {
+		p.Equip = append(p.Equip, parse(t, e))
+	}
+	return p
+}
+
+func TestAC(t *testing.T) {
+	assert.Equal(t, 10, AC(player(t), Protection{}))
+
+	p := player(t,
+		"a - a +1 elven mithril coat (being worn)",
+		"b - an uncursed +0 leather cloak (being worn)",
+		"c - a very rusty +0 dwarvish iron helm (being worn)",
+		"d - a thoroughly corroded +1 small shield (being worn)",
+		"e - a +2 ring of protection (on left hand)",
+		"f - an uncursed +2 long sword (weapon in hand)",
+	)
+	// 10 - 6 - 1 - 0 - 1 - 2, and 3 more for protection.
+	assert.Equal(t, 0, AC(p, Protection{}))
+	assert.Equal(t, -3, AC(p, Protection{Divine: 2, Spell: 1}))
+
+	p.AC = -2
+	assert.Equal(t, 2, UnknownEnchantment(p, Protection{}))
+
+	// Polymorphed players have their form's AC.
+	p.Species = mon.Lookup("xorn")
+	assert.Equal(t, -12, AC(p, Protection{}))
+
+	// Every pair of random boots, gloves and helmet gives 1 AC, whatever it
+	// turns out to be.
+	p = player(t,
+		"a - an uncursed +0 leather armor (being worn)",
+		"b - a pair of combat boots (being worn)",
+		"c - a pair of old gloves (being worn)",
+		"d - a crested helmet (being worn)",
+	)
+	p.AC = 5
+	assert.Equal(t, 5, AC(p, Protection{}))
+	assert.Equal(t, 0, UnknownEnchantment(p, Protection{}))
+
+	// A cloak of protection gives more AC than the other random cloaks, so
+	// we don't know a tattered cape's.
+	p = player(t, "a - a tattered cape (being worn)")
+	p.AC = 9
+	assert.Equal(t, 10, AC(p, Protection{}))
+	assert.Equal(t, 1, UnknownEnchantment(p, Protection{}))
+}
+
+func TestMC(t *testing.T) {
+	assert.Equal(t, 0, MC(player(t)))
+	assert.Equal(t, 1, MC(player(t, "a - a +0 leather cloak (being worn)")))
+	assert.Equal(t, 3, MC(player(t,
+		"a - a +0 elven mithril coat (being worn)",
+		"b - a +0 leather cloak (being worn)")))
+	assert.Equal(t, 2, MC(player(t,
+		"a - a +0 plate mail (being worn)",
+		"b - a +0 fedora (being worn)")))
+
+	// The random cloaks give different MC.
+	assert.Equal(t, 0, MC(player(t, "a - a tattered cape (being worn)")))
+	assert.Equal(t, 0, MC(player(t, "a - a pair of combat boots (being worn)")))
+}
@@ -0,0 +1,364 @@
+// Package combat works out how well the player fights: the chance that a
+// blow lands, and how much damage it does. It follows nethack 3.4.3's
+// find_roll_to_hit, hitval, dmgval and hmon.
+package combat
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jaguilar/nh/model/item"
+	"github.com/jaguilar/nh/model/mon"
+	"github.com/jaguilar/nh/model/pc"
+	"github.com/jaguilar/nh/model/randfunc"
+)
+
+// Attacker is what the combat math needs to know about the player. Most of
+// it isn't on the status line, so the caller has to work it out.
+type Attacker struct {
+	// Str is encoded as on pc.Str18.
+	Str, Dex int
+
+	// XL is the experience level.
+	XL int
+
+	Luck int
+
+	// HitInc and DamInc are the bonuses to hit and damage from rings of
+	// increase accuracy and increase damage.
+	HitInc, DamInc int
+
+	// Skill is the player's skill with the weapon, or at bare-handed
+	// combat if they have none.
+	Skill pc.Skill
+
+	// TwoWeapon is set if the player is fighting with two weapons.
+	// TwoWeaponSkill is their two-weapon combat skill then.
+	TwoWeapon      bool
+	TwoWeaponSkill pc.Skill
+
+	pc.Encumbrance
+}
+
+// AttackerOf returns an Attacker with what p tells us: the rest is left at
+// its zero value, which is an unskilled attacker with no Luck.
+func AttackerOf(p *pc.Player) Attacker {
+	return Attacker{Str: p.Str, Dex: p.Dex, XL: p.XL, Encumbrance: p.Encumbrance}
+}
+
+// abon is the player's bonus to hit for their strength and dexterity.
+func (a Attacker) abon() int {
+	var b int
+	switch {
+	case a.Str < 6:
+		b = -2
+	case a.Str < 8:
+		b = -1
+	case a.Str < 17:
+		b = 0
+	case a.Str <= pc.Str18(50):
+		b = 1
+	case a.Str < pc.Str18(100):
+		b = 2
+	default:
+		b = 3
+	}
+	// Nethack makes it a bit easier for low level characters to hit.
+	if a.XL < 3 {
+		b++
+	}
+	switch {
+	case a.Dex < 4:
+		return b - 3
+	case a.Dex < 6:
+		return b - 2
+	case a.Dex < 8:
+		return b - 1
+	case a.Dex < 14:
+		return b
+	}
+	return b + a.Dex - 14
+}
+
+// dbon is the player's bonus to damage for their strength.
+func (a Attacker) dbon() int {
+	switch {
+	case a.Str < 6:
+		return -1
+	case a.Str < 16:
+		return 0
+	case a.Str < 18:
+		return 1
+	case a.Str == 18:
+		return 2
+	case a.Str <= pc.Str18(75):
+		return 3
+	case a.Str <= pc.Str18(90):
+		return 4
+	case a.Str < pc.Str18(100):
+		return 5
+	}
+	return 6
+}
+
+// skill returns the skill the player attacks with: their two-weapon skill
+// is limited by their skill with the weapon.
+func (a Attacker) skill() pc.Skill {
+	if a.TwoWeapon && a.TwoWeaponSkill < a.Skill {
+		return a.TwoWeaponSkill
+	}
+	return a.Skill
+}
+
+// skillBonus returns the player's skill bonuses to hit and damage with w.
+// Without a weapon, Skill is the player's bare-handed combat skill.
+func (a Attacker) skillBonus(w *item.Item) (hit, dam int) {
+	s := a.skill()
+	if w == nil {
+		b := int(s) - 1
+		if s < pc.Unskilled {
+			b = 0
+		}
+		return (b + 2) / 2, (b + 1) / 2
+	}
+	if a.TwoWeapon {
+		switch s {
+		case pc.Restricted, pc.Unskilled:
+			return -9, -3
+		case pc.Basic:
+			return -7, -1
+		case pc.Skilled:
+			return -5, 0
+		}
+		return -3, 1
+	}
+	switch s {
+	case pc.Restricted, pc.Unskilled:
+		return -4, -2
+	case pc.Basic:
+		return 0, 0
+	case pc.Skilled:
+		return 2, 1
+	}
+	return 3, 2
+}
+
+var (
+	// spears get a bonus to hit against the kebabable classes: xorns,
+	// dragons, jabberwocks, nagas and giants.
+	spears     = set("orcish spear", "spear", "silver spear", "elven spear", "dwarvish spear", "javelin")
+	kebabable  = "XDJNH"
+	picks      = set("pick-axe", "dwarvish mattock")
+	axes       = set("axe", "battle-axe")
+	diggedInto = set("xorn", "earth elemental")
+)
+
+func set(names ...string) map[string]bool {
+	m := make(map[string]bool)
+	for _, n := range names {
+		m[n] = true
+	}
+	return m
+}
+
+// ToHit returns the chance, from 0 to 1, that the player a hits a monster
+// of species s with w. w is nil if the player is fighting bare-handed. The
+// monster is taken to be awake and able to move, and to have its species'
+// AC.
+func ToHit(a Attacker, w *item.Item, s *mon.Species) float64 {
+	tmp := 1 + a.Luck + a.abon() + s.AC + a.HitInc + a.XL
+	if a.Encumbrance != pc.Unencumbered {
+		tmp -= 2*int(a.Encumbrance) - 1
+	}
+	hit, _ := a.skillBonus(w)
+	if w == nil {
+		return chance(tmp+hit, 0)
+	}
+	tmp += hit + w.Class.HitBonus
+	if weapon(w) {
+		tmp += w.Enhancement.Value
+		if w.BUC == item.Blessed && (s.Demon() || s.Undead()) {
+			tmp += 2
+		}
+	}
+	name := w.Class.Name
+	switch {
+	case spears[name] && s.Class != "" && strings.Contains(kebabable, s.Class):
+		tmp += 2
+	case name == "trident" && (s.Class == ";" || s.Class == "S"):
+		tmp += 2
+	case picks[name] && diggedInto[s.Name]:
+		tmp += 2
+	}
+	die := 0
+	if art := w.Artifact(); art != nil && applies(art, s) {
+		die = art.Attack.ToHit
+	}
+	return chance(tmp, die)
+}
+
+// chance returns the chance that tmp, plus a roll of a die-sided die if die
+// isn't 0, beats a d20.
+func chance(tmp, die int) float64 {
+	if die == 0 {
+		return beats(tmp)
+	}
+	var p float64
+	for k := 1; k <= die; k++ {
+		p += beats(tmp + k)
+	}
+	return p / float64(die)
+}
+
+// beats returns the chance that tmp is more than a roll of a d20.
+func beats(tmp int) float64 {
+	switch {
+	case tmp <= 1:
+		return 0
+	case tmp > 20:
+		return 1
+	}
+	return float64(tmp-1) / 20
+}
+
+// Damage returns the damage the player a does to a monster of species s by
+// hitting it with w, or bare-handed if w is nil. Martial arts and
+// thick-skinned monsters aren't modeled.
+func Damage(a Attacker, w *item.Item, s *mon.Species) randfunc.RFunc {
+	_, skill := a.skillBonus(w)
+	bonus := randfunc.Const(a.DamInc + a.dbon() + skill)
+	if w == nil {
+		return randfunc.AtLeast(randfunc.Sum(randfunc.DiceMust("d2"), bonus), 1)
+	}
+	if s.Name == "shade" && w.Class.Material != item.Silver {
+		return randfunc.Const(0)
+	}
+
+	art := w.Artifact()
+	if art != nil && !applies(art, s) {
+		art = nil
+	}
+	double := art != nil && art.Attack.Damage == 0
+
+	dmg := w.Class.SmallDam
+	if s.Large() {
+		dmg = w.Class.LargeDam
+	}
+	if dmg == nil {
+		// Things that aren't weapons do d2.
+		dmg = randfunc.DiceMust("d2")
+	}
+	if weapon(w) {
+		dmg = randfunc.AtLeast(randfunc.Sum(dmg, randfunc.Const(w.Enhancement.Value)), 0)
+		if vs := versus(w, s); vs != nil {
+			if double {
+				// The artifact will double this too, so it's halved.
+				vs = randfunc.Map(vs, func(b int) int {
+					if b > 1 {
+						return (b + 1) / 2
+					}
+					return b
+				})
+			}
+			dmg = randfunc.Sum(dmg, vs)
+		}
+	}
+	if e := erosion(w.Erosion); e > 0 {
+		// Erosion can't take a blow down to 0, but doesn't lift one that's
+		// already there.
+		dmg = randfunc.Map(dmg, func(d int) int {
+			if d > 0 {
+				d -= e
+				if d < 1 {
+					d = 1
+				}
+			}
+			return d
+		})
+	}
+	switch {
+	case double:
+		// Even a blow that did nothing gets 1 more.
+		dmg = randfunc.Map(dmg, func(d int) int {
+			if d < 1 {
+				return d + 1
+			}
+			return 2 * d
+		})
+	case art != nil:
+		dmg = randfunc.Sum(dmg, randfunc.DiceMust(fmt.Sprintf("d%d", art.Attack.Damage)))
+	}
+	return randfunc.AtLeast(randfunc.Sum(dmg, bonus), 1)
+}
+
+// versus returns the extra damage weapon w does to monsters of species s:
+// blessed weapons hurt demons and the undead, axes hurt wood golems, and
+// silver hurts what hates it. It returns nil if there's none.
+func versus(w *item.Item, s *mon.Species) randfunc.RFunc {
+	var vs []randfunc.RFunc
+	if w.BUC == item.Blessed && (s.Demon() || s.Undead()) {
+		vs = append(vs, randfunc.DiceMust("d4"))
+	}
+	if axes[w.Class.Name] && s.Name == "wood golem" {
+		vs = append(vs, randfunc.DiceMust("d4"))
+	}
+	if w.Class.Material == item.Silver && s.HatesSilver() {
+		vs = append(vs, randfunc.DiceMust("d20"))
+	}
+	if len(vs) == 0 {
+		return nil
+	}
+	return randfunc.Sum(vs...)
+}
+
+// weapon returns whether w's enchantment counts when it hits: it's a weapon
+// (or weapon-tool), or a gem or rock.
+func weapon(w *item.Item) bool {
+	return w.Class.Category == item.Weapon || w.Class.Category == item.Gem
+}
+
+// erosion returns how badly eroded e is, from 0 to 3: the worst of its
+// kinds of erosion.
+func erosion(e item.Erosion) int {
+	worst := 0
+	for _, l := range []item.ErosionLevel{e.Rusty, e.Corroded, e.Burnt, e.Rotted} {
+		if int(item.Uneroded-l) > worst {
+			worst = int(item.Uneroded - l)
+		}
+	}
+	return worst
+}
+
+// applies returns whether art's attack bonus applies against s. We don't
+// know monsters' alignments or resistances, so a cross-aligned bonus never
+// applies, and an elemental one always does.
+func applies(art *item.Artifact, s *mon.Species) bool {
+	if art.Attack.Type == item.NoAttack {
+		return false
+	}
+	switch art.Attack.Against {
+	case "":
+	case "orcs":
+		return s.Orc()
+	case "demons":
+		return s.Demon()
+	case "dragons":
+		return s.Dragon()
+	case "lycanthropes":
+		return s.Were()
+	case "giants":
+		return s.Giant()
+	case "ogres":
+		return s.Class == "O"
+	case "trolls":
+		return s.Class == "T"
+	case "undead":
+		return s.Undead()
+	default:
+		return false
+	}
+	if art.Attack.Type == item.DrainLife {
+		return !s.Undead() && !s.Demon() && !s.Were() && s.Name != "Death"
+	}
+	return true
+}
@@ -0,0 +1,91 @@
+package combat
+
+import (
+	"testing"
+
+	"github.com/jaguilar/nh/model/item"
+	"github.com/jaguilar/nh/model/mon"
+	"github.com/jaguilar/nh/model/pc"
+	"github.com/stretchr/testify/assert"
+)
+
+func parse(t *testing.T, s string) *item.Item {
+	i, err := item.NewRegistry().Parse(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return i
+}
+
+func bounds(t *testing.T, a Attacker, w string, s string) [2]int {
+	var i *item.Item
+	if w != "" {
+		i = parse(t, w)
+	}
+	min, max := Damage(a, i, mon.Lookup(s)).Bound()
+	return [2]int{min, max}
+}
+
+func TestToHit(t *testing.T) {
+	a := Attacker{Str: 16, Dex: 10, XL: 1, Skill: pc.Basic}
+	sword := parse(t, "a - an uncursed +0 long sword (weapon in hand)")
+	newt := mon.Lookup("newt")
+
+	// 1 + 1 for a low level + 8 AC + 1 XL beats a d20 roll of 10 or less.
+	assert.InDelta(t, 0.5, ToHit(a, sword, newt), 1e-9)
+
+	a.Luck = 3
+	assert.InDelta(t, 0.65, ToHit(a, sword, newt), 1e-9)
+	a.Luck = 0
+
+	a.Encumbrance = pc.Stressed
+	assert.InDelta(t, 0.35, ToHit(a, sword, newt), 1e-9)
+	a.Encumbrance = pc.Unencumbered
+
+	a.TwoWeapon, a.TwoWeaponSkill = true, pc.Unskilled
+	assert.InDelta(t, 0.05, ToHit(a, sword, newt), 1e-9)
+	a.TwoWeapon = false
+
+	// Excalibur adds a d5 to hit.
+	assert.InDelta(t, 0.5+0.15, ToHit(a, parse(t, "a - the +0 Excalibur"), newt), 1e-9)
+
+	assert.Equal(t, 0.0, ToHit(Attacker{Str: 3, Dex: 3, XL: 1}, sword, mon.Lookup("disenchanter")))
+	assert.Equal(t, 1.0, ToHit(Attacker{Str: 118, Dex: 25, XL: 30, Luck: 13}, sword, newt))
+}
+
+func TestDamage(t *testing.T) {
+	a := Attacker{Str: 16, Dex: 10, XL: 1, Skill: pc.Basic}
+	for _, tc := range []struct {
+		weapon, target string
+		bounds         [2]int
+	}{
+		// d8 and 1 for strength, or d12 against large monsters.
+		{"a - an uncursed +0 long sword", "newt", [2]int{2, 9}},
+		{"a - an uncursed +0 long sword", "purple worm", [2]int{2, 13}},
+		{"a - a +3 long sword", "newt", [2]int{5, 12}},
+		{"a - a -3 long sword", "newt", [2]int{1, 6}},
+		{"a - a very rusty +0 long sword", "newt", [2]int{2, 7}},
+		{"a - a blessed +0 silver saber", "vampire", [2]int{4, 33}},
+		{"a - a blessed +0 silver saber", "newt", [2]int{2, 9}},
+		{"a - the +0 Excalibur", "newt", [2]int{3, 19}},
+		{"a - a +0 elven broadsword named Orcrist", "hill orc", [2]int{5, 21}},
+		{"a - a +0 elven broadsword named Orcrist", "newt", [2]int{3, 11}},
+		{"a - a +0 long sword", "shade", [2]int{0, 0}},
+		{"", "newt", [2]int{3, 4}},
+
+		// Only weapons get the blessed bonus against the undead.
+		{"a - a blessed mirror", "vampire", [2]int{2, 3}},
+		// Erosion doesn't turn a blow that did nothing into 1.
+		{"a - a very rusty -3 dagger", "newt", [2]int{1, 2}},
+		// A double damage artifact adds 1 to a blow that did nothing.
+		{"a - a -10 elven broadsword named Orcrist", "hill orc", [2]int{2, 2}},
+		// The blessed bonus is halved before it's doubled: d8 plus 1 or 2,
+		// doubled, and 1 for strength.
+		{"a - a blessed +0 long sword named Demonbane", "succubus", [2]int{5, 21}},
+	} {
+		assert.Equal(t, tc.bounds, bounds(t, a, tc.weapon, tc.target), tc.weapon+" vs "+tc.target)
+	}
+
+	a.Skill = pc.Unskilled
+	assert.Equal(t, [2]int{1, 7}, bounds(t, a, "a - a +0 long sword", "newt"))
+}
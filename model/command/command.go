@@ -1,6 +1,259 @@
 package command
 
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/jaguilar/nh/model/item"
+)
+
 // Command is an action that the player character can take in the game.
 // Issuing a command normally advances game time, unless there is
 // an error.
+//
+// The keystrokes we send assume nethack's default key bindings with
+// number_pad turned off. That is, movement is on the vi-keys.
 type Command int
+
+// The commands we know how to issue. Commands that take an argument (an item
+// to eat, a direction to zap in, etc.) only send the initial keystroke. Nethack
+// will prompt for the rest.
+const (
+	// Invalid is the zero Command. Issuing it is an error.
+	Invalid Command = iota
+
+	// Movement in each of the eight compass directions.
+	North
+	South
+	East
+	West
+	NorthEast
+	NorthWest
+	SouthEast
+	SouthWest
+
+	// Up and Down climb stairs and ladders.
+	Up
+	Down
+
+	Search
+	Wait
+	PickUp
+	Drop
+	DropMany
+	Eat
+	Quaff
+	Read
+	Zap
+	Apply
+	Wear
+	TakeOff
+	PutOn
+	Remove
+	Wield
+	SwapWeapon
+	TwoWeapon
+	Quiver
+	Throw
+	Fire
+	Cast
+	Pay
+	Engrave
+	Open
+	Close
+	Kick
+	Look
+	Inventory
+	Discoveries
+
+	// Extended commands.
+	Pray
+	Offer
+	Force
+	Loot
+	Untrap
+	Dip
+	Sit
+	Chat
+	Enhance
+
+	// Name names a particular item (the "i" entry of the #name menu).
+	Name
+	Call
+	Turn
+	Jump
+	Ride
+	Quit
+
+	// Escape cancels whatever nethack is doing: a prompt, a menu, and so on.
+	Escape
+
+	// Continue dismisses a --More-- or advances to the next page of a menu.
+	Continue
+
+	// numCommands must remain the last entry.
+	numCommands
+)
+
+// extended formats an extended command as it should be typed.
+func extended(name string) string {
+	return "#" + name + "\n"
+}
+
+// keys is the keystroke sequence that nethack expects for each Command.
+var keys = [numCommands]string{
+	North:     "k",
+	South:     "j",
+	East:      "l",
+	West:      "h",
+	NorthEast: "u",
+	NorthWest: "y",
+	SouthEast: "n",
+	SouthWest: "b",
+
+	Up:   "<",
+	Down: ">",
+
+	Search:      "s",
+	Wait:        ".",
+	PickUp:      ",",
+	Drop:        "d",
+	DropMany:    "D",
+	Eat:         "e",
+	Quaff:       "q",
+	Read:        "r",
+	Zap:         "z",
+	Apply:       "a",
+	Wear:        "W",
+	TakeOff:     "T",
+	PutOn:       "P",
+	Remove:      "R",
+	Wield:       "w",
+	SwapWeapon:  "x",
+	TwoWeapon:   extended("twoweapon"),
+	Quiver:      "Q",
+	Throw:       "t",
+	Fire:        "f",
+	Cast:        "Z",
+	Pay:         "p",
+	Engrave:     "E",
+	Open:        "o",
+	Close:       "c",
+	Kick:        "\x04", // ^D
+	Look:        ":",
+	Inventory:   "i",
+	Discoveries: "\\",
+
+	Pray:    extended("pray"),
+	Offer:   extended("offer"),
+	Force:   extended("force"),
+	Loot:    extended("loot"),
+	Untrap:  extended("untrap"),
+	Dip:     extended("dip"),
+	Sit:     extended("sit"),
+	Chat:    extended("chat"),
+	Enhance: extended("enhance"),
+	Name:    extended("name") + "i",
+	Call:    extended("call"),
+	Turn:    extended("turn"),
+	Jump:    extended("jump"),
+	Ride:    extended("ride"),
+	Quit:    extended("quit"),
+
+	Escape:   "\x1b",
+	Continue: "\r",
+}
+
+// Keys returns the keystrokes that must be sent to nethack to issue c. It
+// returns an error if c is not a known Command.
+func (c Command) Keys() (string, error) {
+	if c <= Invalid || c >= numCommands || keys[c] == "" {
+		return "", fmt.Errorf("unknown command: %d", int(c))
+	}
+	return keys[c], nil
+}
+
+// Direction is an answer to nethack's "In what direction?" prompt. Its value
+// is the key that must be pressed to give that answer.
+type Direction rune
+
+// The directions nethack understands. Self is for zapping yourself, applying
+// a stethoscope to yourself, and so on.
+const (
+	NoDirection Direction = 0
+	DirNorth    Direction = 'k'
+	DirSouth    Direction = 'j'
+	DirEast     Direction = 'l'
+	DirWest     Direction = 'h'
+	DirNE       Direction = 'u'
+	DirNW       Direction = 'y'
+	DirSE       Direction = 'n'
+	DirSW       Direction = 'b'
+	DirUp       Direction = '<'
+	DirDown     Direction = '>'
+	Self        Direction = '.'
+)
+
+// Action is a Command along with the operands nethack may ask for after the
+// command has been issued. Only the operands the command needs have to be
+// filled in. For example, to zap the wand in slot f downward:
+//
+//	Action{Command: Zap, Letter: 'f', Direction: DirDown}
+type Action struct {
+	Command
+
+	// Count is the count prefix for the command ("search 20 times"). Zero
+	// means no count.
+	Count int
+
+	// Letter is the inventory letter to answer "What do you want to ...?"
+	// prompts with. If it is zero, Item's InventoryLetter is used instead.
+	// Some prompts accept other symbols, like '-' for your bare hands. For
+	// Cast, it's the letter of the spell to cast.
+	Letter rune
+
+	// Item is the item the command should act on.
+	Item *item.Item
+
+	// Direction answers "In what direction?".
+	Direction
+
+	// Text is the answer to free-form prompts, like the text to engrave or
+	// the name to give an item.
+	Text string
+
+	// Confirm answers yes to yes-or-no questions, like "Are you sure you
+	// want to pray? [yn] (n)". If it isn't set, they're escaped, which
+	// nethack takes as no.
+	Confirm bool
+}
+
+// Keys returns the keystrokes needed to issue the command, including its
+// count prefix. It does not include any of the answers to prompts.
+func (a Action) Keys() (string, error) {
+	k, err := a.Command.Keys()
+	if err != nil {
+		return "", err
+	}
+	if a.Count < 0 {
+		return "", fmt.Errorf("negative count: %d", a.Count)
+	}
+	if a.Count > 0 {
+		// With number_pad off, a count is just the digits typed before the
+		// command.
+		k = strconv.Itoa(a.Count) + k
+	}
+	return k, nil
+}
+
+// ItemLetter returns the letter that should be used to answer a prompt for
+// an item, or false if the Action doesn't specify an item.
+func (a Action) ItemLetter() (rune, bool) {
+	if a.Letter != 0 {
+		return a.Letter, true
+	}
+	if a.Item != nil && a.Item.InventoryLetter != 0 {
+		return a.Item.InventoryLetter, true
+	}
+	return 0, false
+}
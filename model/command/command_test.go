@@ -0,0 +1,47 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/jaguilar/nh/model/item"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeys(t *testing.T) {
+	for c := Invalid + 1; c < numCommands; c++ {
+		k, err := c.Keys()
+		assert.Nil(t, err, "command %d", c)
+		assert.NotEqual(t, "", k, "command %d", c)
+	}
+
+	k, _ := Pray.Keys()
+	assert.Equal(t, "#pray\n", k)
+}
+
+func TestUnknownKeys(t *testing.T) {
+	for _, c := range []Command{Invalid, numCommands, -1} {
+		_, err := c.Keys()
+		assert.NotNil(t, err)
+	}
+}
+
+func TestActionKeys(t *testing.T) {
+	k, err := Action{Command: Search, Count: 20}.Keys()
+	assert.Nil(t, err)
+	assert.Equal(t, "20s", k)
+
+	_, err = Action{Command: Search, Count: -1}.Keys()
+	assert.NotNil(t, err)
+}
+
+func TestItemLetter(t *testing.T) {
+	_, ok := Action{Command: Eat}.ItemLetter()
+	assert.False(t, ok)
+
+	l, ok := Action{Command: Eat, Item: &item.Item{InventoryLetter: 'f'}}.ItemLetter()
+	assert.True(t, ok)
+	assert.Equal(t, 'f', l)
+
+	l, _ = Action{Command: Engrave, Letter: '-', Item: &item.Item{InventoryLetter: 'f'}}.ItemLetter()
+	assert.Equal(t, '-', l)
+}
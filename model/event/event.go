@@ -0,0 +1,207 @@
+/*
+Package event interprets the messages nethack prints on the top line of the
+screen. Each message is turned into a typed Event, so that bots can switch on
+what happened rather than matching strings.
+
+Only a catalogue of common messages is understood. Anything else is reported
+as an Other event, which still carries the message text.
+*/
+package event
+
+// Event is something nethack told us about.
+type Event interface {
+	// Text is the message the Event was parsed from.
+	Text() string
+}
+
+// You is the name we give to the player character in events that involve
+// it. For example, when the player hits a jackal, the event is
+// Hit{Attacker: You, Defender: "jackal"}.
+const You = "you"
+
+// Someone is the name we give to a monster that we cannot see. Nethack calls
+// such monsters "it".
+const Someone = "it"
+
+// text is embedded in each Event to remember the message.
+type text string
+
+// Text is part of the Event interface.
+func (t text) Text() string { return string(t) }
+
+// Other is a message that we don't have a more specific Event for.
+type Other struct {
+	text
+}
+
+// Hit is a successful melee attack or missile hit.
+type Hit struct {
+	text
+	Attacker, Defender string
+}
+
+// Miss is an attack that missed.
+type Miss struct {
+	text
+	Attacker, Defender string
+}
+
+// Kill is the death of a monster.
+type Kill struct {
+	text
+
+	// Killer is You if the player killed the monster. Otherwise it is
+	// empty, because nethack doesn't tell us who did it.
+	Killer string
+	Victim string
+}
+
+// Hurt is damage to the player from something other than a monster's melee
+// attack, like an arrow trap or a falling rock.
+type Hurt struct {
+	text
+
+	// Source is what hurt the player, e.g. "an arrow".
+	Source string
+}
+
+// Condition is a status condition that can come and go, like confusion or
+// stoning. The names match the ones nethack shows on the status line where
+// there is one.
+type Condition string
+
+// The conditions we recognize messages for.
+const (
+	Conf      Condition = "Conf"
+	Stun      Condition = "Stun"
+	Blind     Condition = "Blind"
+	Hallu     Condition = "Hallu"
+	Ill       Condition = "Ill"
+	FoodPois  Condition = "FoodPois"
+	Slime     Condition = "Slime"
+	Stone     Condition = "Stone"
+	Strangled Condition = "Strangled"
+	Lycanthr  Condition = "Lycanthropy"
+	Hungry    Condition = "Hungry"
+	Weak      Condition = "Weak"
+	Fainting  Condition = "Fainting"
+)
+
+// StatusChange is the onset or end of a Condition.
+type StatusChange struct {
+	text
+	Condition
+
+	// On is true if the condition began, false if it ended.
+	On bool
+}
+
+// Intrinsic is an intrinsic property the player can gain or lose.
+type Intrinsic string
+
+// The intrinsics we recognize messages for.
+const (
+	FireRes         Intrinsic = "fire resistance"
+	ColdRes         Intrinsic = "cold resistance"
+	SleepRes        Intrinsic = "sleep resistance"
+	ShockRes        Intrinsic = "shock resistance"
+	PoisonRes       Intrinsic = "poison resistance"
+	DisintRes       Intrinsic = "disintegration resistance"
+	Teleportitis    Intrinsic = "teleportitis"
+	TeleportControl Intrinsic = "teleport control"
+	Telepathy       Intrinsic = "telepathy"
+	Speed           Intrinsic = "speed"
+	Stealth         Intrinsic = "stealth"
+	SeeInvisible    Intrinsic = "see invisible"
+	Invisibility    Intrinsic = "invisibility"
+	Warning         Intrinsic = "warning"
+	Searching       Intrinsic = "searching"
+)
+
+// IntrinsicChange is the gain or loss of an Intrinsic.
+type IntrinsicChange struct {
+	text
+	Intrinsic
+	Gained bool
+}
+
+// ShopEntry is entering a shop.
+type ShopEntry struct {
+	text
+	Shopkeeper string
+
+	// Shop is the kind of shop, as nethack describes it ("general store").
+	Shop string
+}
+
+// RoomEntry is entering a special room that isn't a shop.
+type RoomEntry struct {
+	text
+
+	// Room is the kind of room: "treasure zoo", "throne room", "beehive",
+	// and so on.
+	Room string
+}
+
+// LevelFeeling is a message that hints at what's on the current level.
+type LevelFeeling struct {
+	text
+
+	// Feature is what the message implies exists on the level: "vault",
+	// "fountain", "shop", etc.
+	Feature string
+}
+
+// Pickup is an item arriving in the player's inventory.
+type Pickup struct {
+	text
+	Letter rune
+
+	// Item is the item's description, exactly as nethack printed it.
+	Item string
+}
+
+// SeeHere is the description of the single item on the player's square.
+type SeeHere struct {
+	text
+	Item string
+}
+
+// Fall is the player falling through a trap door or hole to a lower level.
+type Fall struct {
+	text
+}
+
+// Portal is the player being carried to another level by a magic portal.
+type Portal struct {
+	text
+}
+
+// BuyOffer is a shopkeeper telling us what an item we picked up costs.
+type BuyOffer struct {
+	text
+
+	// Price is the price of one of the items.
+	Price int
+
+	// Item is the item's description, as nethack printed it. It's always
+	// singular, and has no article, quantity or enchantment.
+	Item string
+
+	// Angry is set if the shopkeeper is angry with us, which puts the price
+	// up by a third.
+	Angry bool
+}
+
+// SellOffer is a shopkeeper offering to buy an item we dropped.
+type SellOffer struct {
+	text
+	Shopkeeper string
+
+	// Price is what's offered for the whole stack.
+	Price int
+
+	// Item is the item's description, as nethack printed it, without an
+	// article, quantity or enchantment. It's plural if the stack is.
+	Item string
+}
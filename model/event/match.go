@@ -0,0 +1,247 @@
+package event
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// matcher turns a message into an Event if re matches it. The submatches of
+// re are passed to make.
+type matcher struct {
+	re   *regexp.Regexp
+	make func(t text, m []string) Event
+}
+
+// Parse returns the Event for a message. The message should be a single
+// sentence, as produced by splitting the top line. Messages we don't
+// recognize are returned as Other.
+func Parse(msg string) Event {
+	for _, m := range matchers {
+		if s := m.re.FindStringSubmatch(msg); s != nil {
+			return m.make(text(msg), s)
+		}
+	}
+	return Other{text(msg)}
+}
+
+// monster normalizes a monster name the way it appears in a message: "The
+// jackal" and "the jackal" become "jackal", "It" becomes Someone.
+func monster(s string) string {
+	s = strings.TrimPrefix(s, "The ")
+	s = strings.TrimPrefix(s, "the ")
+	if s == "It" || s == "it" {
+		return Someone
+	}
+	return s
+}
+
+// atoi converts a number matched by a regexp, which is known to be valid.
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+// exact returns a matcher for a message that never varies.
+func exact(msg string, make func(t text) Event) matcher {
+	return matcher{
+		re:   regexp.MustCompile("^" + regexp.QuoteMeta(msg) + "$"),
+		make: func(t text, _ []string) Event { return make(t) },
+	}
+}
+
+func status(msg string, c Condition, on bool) matcher {
+	return exact(msg, func(t text) Event { return StatusChange{t, c, on} })
+}
+
+func intrinsic(msg string, i Intrinsic, gained bool) matcher {
+	return exact(msg, func(t text) Event { return IntrinsicChange{t, i, gained} })
+}
+
+func feeling(msg, feature string) matcher {
+	return exact(msg, func(t text) Event { return LevelFeeling{t, feature} })
+}
+
+func room(msg, r string) matcher {
+	return exact(msg, func(t text) Event { return RoomEntry{t, r} })
+}
+
+// The verbs nethack uses for melee hits, by the player and by monsters.
+const (
+	yourHitVerbs    = `hit|smite|strike|kick|bite|butt|sting|touch|claw|punch|bash`
+	monsterHitVerbs = `hits|smites|kicks|bites|butts|stings|touches|claws|scratches|punches|lashes|thrusts|swings`
+)
+
+var matchers = []matcher{
+	// Combat.
+	{regexp.MustCompile(`^You (?:` + yourHitVerbs + `) (.+?)[.!]$`), func(t text, m []string) Event {
+		return Hit{t, You, monster(m[1])}
+	}},
+	{regexp.MustCompile(`^You miss (.+?)\.$`), func(t text, m []string) Event {
+		return Miss{t, You, monster(m[1])}
+	}},
+	{regexp.MustCompile(`^You (?:kill|destroy) (.+?)!$`), func(t text, m []string) Event {
+		return Kill{t, You, monster(m[1])}
+	}},
+	{regexp.MustCompile(`^(.+?) is (?:killed|destroyed)!$`), func(t text, m []string) Event {
+		return Kill{t, "", monster(m[1])}
+	}},
+	{regexp.MustCompile(`^You are (?:hit|almost hit) by (.+?)[.!]$`), func(t text, m []string) Event {
+		if strings.HasPrefix(string(t), "You are almost") {
+			return Miss{t, monster(m[1]), You}
+		}
+		return Hurt{t, m[1]}
+	}},
+	{regexp.MustCompile(`^(.+?) misses you\.$`), func(t text, m []string) Event {
+		return Miss{t, monster(m[1]), You}
+	}},
+	{regexp.MustCompile(`^(.+?) (?:just )?misses!$`), func(t text, m []string) Event {
+		return Miss{t, monster(m[1]), You}
+	}},
+	{regexp.MustCompile(`^(.+?) (?:` + monsterHitVerbs + `)(?: you)?!$`), func(t text, m []string) Event {
+		return Hit{t, monster(m[1]), You}
+	}},
+
+	// Items.
+	{regexp.MustCompile(`^([a-zA-Z$]) - (.+?)\.?$`), func(t text, m []string) Event {
+		return Pickup{t, []rune(m[1])[0], m[2]}
+	}},
+	{regexp.MustCompile(`^You see here (.+)\.$`), func(t text, m []string) Event {
+		return SeeHere{t, m[1]}
+	}},
+
+	// Shopping. A shopkeeper who is short of money offers "only" what they
+	// can afford, which isn't the item's price, so we leave those alone.
+	{regexp.MustCompile(`^For you, (.+?); only (\d+) zorkmids? (?:for this|per) (.+)\.$`), func(t text, m []string) Event {
+		return BuyOffer{t, atoi(m[2]), m[3], m[1] == "scum"}
+	}},
+	{regexp.MustCompile(`^(.+?) offers (\d+) gold pieces? for your (.+)\.$`), func(t text, m []string) Event {
+		return SellOffer{t, m[1], atoi(m[2]), m[3]}
+	}},
+
+	// Leaving the level.
+	{regexp.MustCompile(`^You fall through`), func(t text, m []string) Event {
+		return Fall{t}
+	}},
+	exact("A trap door opens up under you!", func(t text) Event { return Fall{t} }),
+	exact("There's a gaping hole under you!", func(t text) Event { return Fall{t} }),
+	exact("You activated a magic portal!", func(t text) Event { return Portal{t} }),
+
+	// Shops and special rooms.
+	{regexp.MustCompile(`^Welcome to (.+?)'s? (treasure zoo)!$`), func(t text, m []string) Event {
+		return RoomEntry{t, m[2]}
+	}},
+	{regexp.MustCompile(`^Welcome(?: again)? to (.+?)'s? (.+?)!$`), func(t text, m []string) Event {
+		return ShopEntry{t, m[1], m[2]}
+	}},
+	room("You enter an opulent throne room!", "throne room"),
+	room("You enter a beehive!", "beehive"),
+	room("You enter a disgusting nest!", "cockatrice nest"),
+	room("You enter an anthole!", "anthole"),
+	room("You enter a giant barracks!", "barracks"),
+	room("You enter a military barracks!", "barracks"),
+	room("You enter a graveyard!", "graveyard"),
+	room("You have an eerie feeling...", "graveyard"),
+
+	// Level sounds.
+	feeling("You hear the footsteps of a guard on patrol.", "vault"),
+	feeling("You hear someone counting money.", "vault"),
+	feeling("You hear Ebenezer Scrooge!", "vault"),
+	feeling("You hear the quarterback calling the play.", "vault"),
+	feeling("You hear bubbling water.", "fountain"),
+	feeling("You hear water falling on coins.", "fountain"),
+	feeling("You hear the splashing of a naiad.", "fountain"),
+	feeling("You hear a soda fountain!", "fountain"),
+	feeling("You hear a slow drip.", "sink"),
+	feeling("You hear a gurgling noise.", "sink"),
+	feeling("You hear dishes being washed!", "sink"),
+	feeling("You hear someone cursing shoplifters.", "shop"),
+	feeling("You hear the chime of a cash register.", "shop"),
+	feeling("You hear Neiman and Marcus arguing!", "shop"),
+	feeling("You hear the tones of courtly conversation.", "throne room"),
+	feeling("You hear a sceptre pounded in judgment.", "throne room"),
+	feeling("You hear Someone shout \"Off with his head!\"", "throne room"),
+	feeling("You hear Queen Beruthiel's cats!", "throne room"),
+	feeling("You hear a low buzzing.", "beehive"),
+	feeling("You hear an angry drone.", "beehive"),
+	feeling("You hear bees in your bonnet!", "beehive"),
+	feeling("You hear blades being honed.", "barracks"),
+	feeling("You hear loud snoring.", "barracks"),
+	feeling("You hear dice being thrown.", "barracks"),
+	feeling("You hear General MacArthur!", "barracks"),
+	feeling("You hear a sound reminiscent of an elephant stepping on a peanut.", "zoo"),
+	feeling("You hear a sound reminiscent of a seal barking.", "zoo"),
+	feeling("You hear Doctor Dolittle!", "zoo"),
+	feeling("You hear a strange wind.", "oracle"),
+	feeling("You hear convulsive ravings.", "oracle"),
+	feeling("You hear snoring snakes.", "oracle"),
+	feeling("You hear someone say \"No more woodchucks!\"", "oracle"),
+	feeling("You hear a loud ZOT!", "oracle"),
+	feeling("You hear mosquitoes!", "swamp"),
+	feeling("You smell marsh gas!", "swamp"),
+	feeling("You hear Donald Duck!", "swamp"),
+	feeling("You have a strange forbidding feeling...", "temple"),
+	feeling("You have an uncanny feeling...", "temple"),
+	feeling("You hear a door open.", "door"),
+	// "It is hot here.  You smell smoke..." is split into two messages.
+	feeling("It is hot here.", "valley"),
+	feeling("You enter what seems to be an older, more primitive world.", "rogue level"),
+
+	// Status conditions.
+	status("You feel somewhat dizzy.", Conf, true),
+	status("You feel less confused now.", Conf, false),
+	status("You stagger...", Stun, true),
+	status("You feel a bit steadier now.", Stun, false),
+	status("You can't see any more.", Blind, true),
+	status("A cloud of darkness falls upon you.", Blind, true),
+	status("You can see again.", Blind, false),
+	// "Oh wow!  Everything looks so cosmic!" is split into two messages.
+	status("Everything looks so cosmic!", Hallu, true),
+	status("Everything looks SO boring now.", Hallu, false),
+	status("You feel deathly sick.", Ill, true),
+	status("You feel much better.", Ill, false),
+	status("You feel better.", Ill, false),
+	status("Ulch - that meat was tainted!", FoodPois, true),
+	status("You are turning a little green.", Slime, true),
+	status("You are slowing down.", Stone, true),
+	status("Your limbs are stiffening.", Stone, true),
+	status("You feel limber!", Stone, false),
+	status("It constricts your throat!", Strangled, true),
+	status("You find it hard to breathe.", Strangled, true),
+	status("You can breathe more easily!", Strangled, false),
+	status("You feel feverish.", Lycanthr, true),
+	status("You feel purified.", Lycanthr, false),
+	status("You are beginning to feel hungry.", Hungry, true),
+	status("You only feel hungry now.", Hungry, true),
+	status("You are beginning to feel weak.", Weak, true),
+	status("You faint from lack of food.", Fainting, true),
+	status("You regain consciousness.", Fainting, false),
+
+	// Intrinsics.
+	intrinsic("You feel a momentary chill.", FireRes, true),
+	intrinsic("You feel cooler.", FireRes, false),
+	intrinsic("You feel full of hot air.", ColdRes, true),
+	intrinsic("You feel warmer.", ColdRes, false),
+	intrinsic("You feel wide awake.", SleepRes, true),
+	intrinsic("You feel tired!", SleepRes, false),
+	intrinsic("Your health currently feels amplified!", ShockRes, true),
+	intrinsic("You feel conductive.", ShockRes, false),
+	intrinsic("You feel healthy.", PoisonRes, true),
+	intrinsic("You feel a little sick!", PoisonRes, false),
+	intrinsic("You feel very firm.", DisintRes, true),
+	intrinsic("You feel very jumpy.", Teleportitis, true),
+	intrinsic("You feel less jumpy.", Teleportitis, false),
+	intrinsic("You feel in control of yourself.", TeleportControl, true),
+	intrinsic("You feel less in control of yourself.", TeleportControl, false),
+	intrinsic("You feel a strange mental acuity.", Telepathy, true),
+	intrinsic("Your senses fail!", Telepathy, false),
+	intrinsic("You feel quick!", Speed, true),
+	intrinsic("You feel slow!", Speed, false),
+	intrinsic("You feel stealthy!", Stealth, true),
+	intrinsic("You feel clumsy.", Stealth, false),
+	intrinsic("You feel perceptive!", Searching, true),
+	intrinsic("You feel sensitive!", Warning, true),
+	intrinsic("You feel hidden!", Invisibility, true),
+	intrinsic("You feel paranoid.", Invisibility, false),
+	intrinsic("You thought you saw something!", SeeInvisible, true),
+}
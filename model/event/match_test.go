@@ -0,0 +1,48 @@
+package event
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	for _, tc := range []struct {
+		msg  string
+		want Event
+	}{
+		{"You hit the jackal.", Hit{"You hit the jackal.", You, "jackal"}},
+		{"You smite the Grey-elf!", Hit{"You smite the Grey-elf!", You, "Grey-elf"}},
+		{"You miss it.", Miss{"You miss it.", You, Someone}},
+		{"The newt bites!", Hit{"The newt bites!", "newt", You}},
+		{"It hits!", Hit{"It hits!", Someone, You}},
+		{"The jackal misses!", Miss{"The jackal misses!", "jackal", You}},
+		{"The arrow misses you.", Miss{"The arrow misses you.", "arrow", You}},
+		{"You are hit by a dart.", Hurt{"You are hit by a dart.", "a dart"}},
+		{"You kill the jackal!", Kill{"You kill the jackal!", You, "jackal"}},
+		{"The gnome lord is killed!", Kill{"The gnome lord is killed!", "", "gnome lord"}},
+		{"You feel feverish.", StatusChange{"You feel feverish.", Lycanthr, true}},
+		{"You feel a bit steadier now.", StatusChange{"You feel a bit steadier now.", Stun, false}},
+		{"You feel a momentary chill.", IntrinsicChange{"You feel a momentary chill.", FireRes, true}},
+		{"You hear the footsteps of a guard on patrol.", LevelFeeling{"You hear the footsteps of a guard on patrol.", "vault"}},
+		{"Welcome to David's treasure zoo!", RoomEntry{"Welcome to David's treasure zoo!", "treasure zoo"}},
+		{"Welcome to Asidonhopo's general store!", ShopEntry{"Welcome to Asidonhopo's general store!", "Asidonhopo", "general store"}},
+		{"f - 2 food rations.", Pickup{"f - 2 food rations.", 'f', "2 food rations"}},
+		{"You see here a +1 long sword.", SeeHere{"You see here a +1 long sword.", "a +1 long sword"}},
+		{"For you, esteemed sir; only 133 zorkmids for this scroll labeled FOOBIE BLETCH.",
+			BuyOffer{"For you, esteemed sir; only 133 zorkmids for this scroll labeled FOOBIE BLETCH.", 133, "scroll labeled FOOBIE BLETCH", false}},
+		{"For you, scum; only 8 zorkmids per dart.", BuyOffer{"For you, scum; only 8 zorkmids per dart.", 8, "dart", true}},
+		{"Vlad offers 50 gold pieces for your platinum wand.",
+			SellOffer{"Vlad offers 50 gold pieces for your platinum wand.", "Vlad", 50, "platinum wand"}},
+		{"Vlad offers only 5 gold pieces for your platinum wand.",
+			Other{"Vlad offers only 5 gold pieces for your platinum wand."}},
+		{"You fall through...", Fall{"You fall through..."}},
+		{"You activated a magic portal!", Portal{"You activated a magic portal!"}},
+		{"It is hot here.", LevelFeeling{"It is hot here.", "valley"}},
+		{"You hear a nearby zap.", Other{"You hear a nearby zap."}},
+	} {
+		got := Parse(tc.msg)
+		assert.Equal(t, tc.want, got, tc.msg)
+		assert.Equal(t, tc.msg, got.Text())
+	}
+}
@@ -5,18 +5,20 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"strings"
 	"sync"
-	"time"
 
 	"github.com/jaguilar/nh/model/command"
+	"github.com/jaguilar/nh/model/event"
 	"github.com/jaguilar/nh/model/internal/screen"
+	"github.com/jaguilar/nh/model/item"
 	"github.com/jaguilar/nh/model/level"
 	"github.com/jaguilar/nh/model/pc"
 	"github.com/jaguilar/vt100"
 )
 
 var (
-	// ErrUnexpectedResume is returned from Issue when the underlying game
+	// ErrUnexpectedResume is returned from Do when the underlying game
 	// resumed sending data unexpectedly. The correct thing to do when
 	// this happens is to discard the previous decision you made and return
 	// to LockWhenIdle.
@@ -63,6 +65,18 @@ type WindowSize struct {
 type TurnEvents struct {
 	// All the events that happened on this turn.
 	E []string
+
+	// Typed is the interpretation of each message in E. Typed[i] is the
+	// event for E[i].
+	Typed []event.Event
+}
+
+// add appends messages to the turn, interpreting each of them.
+func (t *TurnEvents) add(msgs ...string) {
+	for _, m := range msgs {
+		t.E = append(t.E, m)
+		t.Typed = append(t.Typed, event.Parse(m))
+	}
 }
 
 type menuContext int
@@ -96,6 +110,16 @@ type Game struct {
 	// Level contains all the levels we've seen.
 	Level map[level.LevelID]*level.Level
 
+	// Overview is what we know of the layout of the dungeon, including which
+	// level the player is on.
+	Overview level.Overview
+
+	// Registry is what we know about the item classes in this game, and
+	// which appearance is which.
+	Registry *item.Registry
+
+	// Events are the messages nethack showed in response to each command, most
+	// recent first. Only the last MaxEventLookback commands are kept.
 	Events *TurnEventsList
 
 	// in and out are the input stream from and output stream to nethack.
@@ -106,6 +130,24 @@ type Game struct {
 
 	lastMenu screen.MenuFormat
 
+	// lastCmd is the most recent command we issued. It's reset to Invalid
+	// when we dismiss a menu it opened, since it no longer tells the
+	// IdleDetector anything about the screen.
+	lastCmd command.Command
+
+	// turn holds the messages nethack has shown since we issued lastCmd.
+	turn []string
+
+	// portal is set while the message that we've taken a magic portal is
+	// still to be seen, so that we only move once for it.
+	portal bool
+
+	opts Options
+
+	// resumed is set when nethack sends us data while we weren't waiting for
+	// any. It is cleared by LockWhenIdle.
+	resumed bool
+
 	inputCommands <-chan vt100.Command
 	inputErrs     <-chan error
 }
@@ -116,8 +158,11 @@ type Game struct {
 // should be through this instance of Game.
 //
 // It is safe to examine this between calls to Do, but not during any given Do
-// call.
-func NewGame(in io.Reader, out io.Writer, win WindowSize) (*Game, error) {
+// call. If other goroutines need to examine the Game, use LockWhenIdle and
+// Unlock to coordinate with them.
+//
+// opts may be nil, in which case the defaults are used.
+func NewGame(in io.Reader, out io.Writer, win WindowSize, opts *Options) (*Game, error) {
 	if win.Y < 24 || win.X < 80 {
 		panic(fmt.Errorf("screen dimensions must be at least 24x80 (got: %dx%d)", win.Y, win.X))
 	}
@@ -125,14 +170,16 @@ func NewGame(in io.Reader, out io.Writer, win WindowSize) (*Game, error) {
 	cmds, errs := inputUntilClosed(in)
 	g := &Game{
 		Level:         make(map[level.LevelID]*level.Level),
+		Registry:      item.NewRegistry(),
 		Events:        NewTurnEventsList(),
 		out:           out,
 		vt:            vt100.NewVT100(win.Y, win.X),
 		inputCommands: cmds,
 		inputErrs:     errs,
+		opts:          opts.withDefaults(),
 	}
 
-	return g, g.waitIdle()
+	return g, g.waitIdle(false)
 }
 
 func inputUntilClosed(in io.Reader) (<-chan vt100.Command, <-chan error) {
@@ -156,48 +203,75 @@ func inputUntilClosed(in io.Reader) (<-chan vt100.Command, <-chan error) {
 	return cmds, errs
 }
 
-// waitIdle waits until a nethack frame has finished drawing.
-func (g *Game) waitIdle() error {
-	timeout := time.Millisecond * 20
-	timer := time.NewTimer(timeout)
-	for {
-		select {
-		case cmd, ok := <-g.inputCommands:
-			if !ok {
-				// Only way this can happen is io.EOF from the input channel.
-				return ErrGameOver
-			}
+// process applies a single terminal command to the vt100.
+func (g *Game) process(cmd vt100.Command) error {
+	g.vtMu.Lock()
+	err := g.vt.Process(cmd)
+	g.vtMu.Unlock()
 
-			g.vtMu.Lock()
-			err := g.vt.Process(cmd)
-			g.vtMu.Unlock()
+	// We ignore unsupported errors from the VT100. If such an error results
+	// in game state corruption, please file an issue with jaguilar/vt100.
+	if _, ok := err.(vt100.UnsupportedError); ok {
+		return nil
+	}
+	return err
+}
 
-			if err != nil {
-				// We ignore unsupported errors from the VT100. If such an error results
-				// in game state corruption, please file an issue with jaguilar/vt100.
-				if _, ok := err.(vt100.UnsupportedError); ok {
-					continue
-				}
-				return err
-			}
-		case err, ok := <-g.inputErrs:
-			if !ok {
-				return ErrGameOver
-			}
-			return err
-		case <-timer.C:
-			// First cut: we'll consider the frame idle if 20ms have passed since
-			// the last update. This will likely not be sufficient for remote nethack.
-			// Ideas for doing it better: given the last command, we know where the cursor
-			// should end up (usually at the end of the first line, on the player, or at the
-			// end of a list). We can use knowledge of where the cursor is to reduce
-			// the timeout, and use a long timeout for when the cursor is anywhere else.
-			return nil
+// checkResume reports whether nethack has sent anything since the last time
+// we waited for it to go idle. Anything it did send is applied to the vt100.
+// Once a resume is detected, it is remembered until the next LockWhenIdle.
+func (g *Game) checkResume() (bool, error) {
+	if g.resumed {
+		return true, nil
+	}
+	select {
+	case cmd, ok := <-g.inputCommands:
+		if !ok {
+			return false, ErrGameOver
+		}
+		g.resumed = true
+		return true, g.process(cmd)
+	case err, ok := <-g.inputErrs:
+		if !ok {
+			return false, ErrGameOver
 		}
-		timer.Reset(timeout)
+		return false, err
+	default:
+		return false, nil
 	}
 }
 
+// LockWhenIdle waits until nethack has finished drawing, then locks the Game
+// so its state can be examined without racing with other users of the Game.
+// This is how a bot should begin each of its turns: lock, look at the model,
+// decide what to do, Do it, then Unlock.
+//
+// LockWhenIdle also clears the unexpected resume condition. See
+// ErrUnexpectedResume. If an error is returned, the Game is not locked.
+func (g *Game) LockWhenIdle() error {
+	g.mu.Lock()
+	if err := g.waitIdle(false); err != nil {
+		g.mu.Unlock()
+		return err
+	}
+	if g.resumed {
+		// Whatever nethack said when it resumed belongs to the last command.
+		g.recordMessage()
+		if e := g.Events.Front(); e != nil {
+			e.Value.add(g.turn...)
+		}
+		g.turn = nil
+	}
+	g.resumed = false
+	g.lastMenu = screen.Screen(g.vt.Content).NextMenu(g.lastMenu)
+	return nil
+}
+
+// Unlock releases the lock taken by LockWhenIdle.
+func (g *Game) Unlock() {
+	g.mu.Unlock()
+}
+
 // Do a Command. errors are only returned if you issued an illegal command or
 // something went wrong with nethack (e.g. it was killed out from under us).
 // To see if your command worked or did what you intended, you'll need to check
@@ -206,10 +280,289 @@ func (g *Game) waitIdle() error {
 // To exit the game (even if you died, or the game crashed), you need to send
 // command.Quit.
 func (g *Game) Do(c command.Command) error {
-	// TODO(jaguilar): decide how to issue a command.
-	// TODO(jaguilar): ensure that the terminal didn't resume since the previous command.
+	return g.DoAction(command.Action{Command: c})
+}
+
+// DoAction is like Do, but it answers the questions nethack asks after the
+// command is issued using the operands in a. If nethack asks something that a
+// doesn't provide an answer for, the question is cancelled and a *PromptError
+// is returned.
+//
+// If nethack drew anything since the last command finished (for example,
+// because a multi-turn action was interrupted), the command is not sent and
+// ErrUnexpectedResume is returned. The command will keep being refused until
+// LockWhenIdle is called.
+func (g *Game) DoAction(a command.Action) error {
+	keys, err := a.Keys()
+	if err != nil {
+		return err
+	}
+
+	resumed, err := g.checkResume()
+	if err != nil {
+		return err
+	}
+	if resumed {
+		return ErrUnexpectedResume
+	}
+
+	g.lastCmd = a.Command
+	defer g.pushEvents()
+	if err := g.send(keys); err != nil {
+		return err
+	}
+	if err := g.waitIdle(true); err != nil {
+		return err
+	}
+	// A shopkeeper's offer comes with a question we may not answer, but the
+	// price tells us something either way.
+	err = g.answerPrompts(a)
+	g.identifyByPrice()
+	if err != nil {
+		return err
+	}
 	g.lastMenu = screen.Screen(g.vt.Content).NextMenu(g.lastMenu)
-	return g.waitIdle()
+	switch {
+	case a.Command == command.Inventory:
+		return g.readInventory()
+	case g.lastMenu == screen.MenuSpell:
+		return g.readSpells(a)
+	}
+	return nil
+}
+
+// readInventory reads the inventory menu into Pack, one page at a time, and
+// then dismisses it. Equip is set to the items in use.
+func (g *Game) readInventory() error {
+	if g.lastMenu != screen.MenuInv {
+		for _, m := range g.turn {
+			if m == "Not carrying anything." {
+				g.Pack, g.Equip = nil, nil
+			}
+		}
+		return nil
+	}
+
+	var (
+		pack []*item.Item
+		cat  item.Category
+	)
+	for {
+		s := screen.Screen(g.vt.Content)
+
+		// Lines we can't parse are dropped. We'd rather have most of the
+		// inventory than none of it.
+		items, _ := s.ParseItems(g.Registry, cat)
+		if len(items) > 0 {
+			cat = items[len(items)-1].Class.Category
+		}
+		pack = append(pack, items...)
+
+		page, pages := s.MenuPage()
+		if page >= pages {
+			break
+		}
+		if err := g.send(">"); err != nil {
+			return err
+		}
+		if err := g.waitIdle(true); err != nil {
+			return err
+		}
+	}
+	for _, i := range pack {
+		i.Died = g.died(i)
+	}
+	g.Pack, g.Equip = pack, nil
+	for _, i := range pack {
+		if i.Use != item.NotInUse {
+			g.Equip = append(g.Equip, i)
+		}
+	}
+
+	g.lastCmd = command.Invalid
+	if err := g.send("\x1b"); err != nil {
+		return err
+	}
+	if err := g.waitIdle(true); err != nil {
+		return err
+	}
+	g.lastMenu = screen.Screen(g.vt.Content).NextMenu(g.lastMenu)
+	return nil
+}
+
+// died returns when the monster whose corpse i is, newly read from the
+// inventory, died: what we knew of it the last time we read the inventory,
+// or, if it's just been picked up, what we knew of the corpse on the
+// player's square. It returns 0 if we don't know.
+func (g *Game) died(i *item.Item) int {
+	if i.Class.Name != "corpse" {
+		return 0
+	}
+	for _, p := range g.Pack {
+		if p.InventoryLetter == i.InventoryLetter && p.Class == i.Class && p.Species == i.Species {
+			return p.Died
+		}
+	}
+	if l := g.Level[g.Overview.Current]; l != nil {
+		for _, f := range l.Map[g.Y][g.X].Items {
+			if f.Class != nil && f.Class.Name == "corpse" && (f.Species == "" || f.Species == i.Species) {
+				return f.Died
+			}
+		}
+	}
+	return 0
+}
+
+// readSpells reads the spell menu into Spells. If a has a Letter, the spell
+// with that letter is cast, and any prompts that follow are answered from
+// a. Otherwise the menu is dismissed.
+func (g *Game) readSpells(a command.Action) error {
+	var spells []pc.Spell
+	for {
+		s := screen.Screen(g.vt.Content)
+
+		// As with the inventory, lines we can't parse are dropped.
+		page, _ := s.ParseSpells()
+		spells = append(spells, page...)
+
+		n, pages := s.MenuPage()
+		if n >= pages {
+			break
+		}
+		if err := g.send(">"); err != nil {
+			return err
+		}
+		if err := g.waitIdle(true); err != nil {
+			return err
+		}
+	}
+	g.Spells = spells
+
+	answer := "\x1b"
+	if a.Letter != 0 {
+		answer = string(a.Letter)
+	}
+	g.lastCmd = command.Invalid
+	if err := g.send(answer); err != nil {
+		return err
+	}
+	if err := g.waitIdle(true); err != nil {
+		return err
+	}
+	g.lastMenu = screen.Screen(g.vt.Content).NextMenu(g.lastMenu)
+	if a.Letter == 0 {
+		return nil
+	}
+	return g.answerPrompts(command.Action{Direction: a.Direction, Text: a.Text, Confirm: a.Confirm})
+}
+
+// PromptError is returned when nethack asks a question that we weren't
+// given an answer for.
+type PromptError struct {
+	// Prompt is the text of the question.
+	Prompt string
+}
+
+func (e *PromptError) Error() string {
+	return fmt.Sprintf("no answer for prompt: %q", e.Prompt)
+}
+
+// answerPrompts answers each question nethack asks until it stops asking.
+// Each kind of question is only answered once, so that we don't loop forever
+// if nethack doesn't like our answer and asks again.
+func (g *Game) answerPrompts(a command.Action) error {
+	answered := make(map[screen.Prompt]bool)
+	for {
+		p, text := screen.Screen(g.vt.Content).Prompt()
+
+		var answer string
+		switch p {
+		case screen.PromptNone:
+			g.recordMessage()
+			return nil
+		case screen.PromptMore:
+			g.recordMessage()
+			answer = "\r"
+		case screen.PromptItem:
+			if l, ok := a.ItemLetter(); ok && !answered[p] {
+				answer = string(l)
+			}
+		case screen.PromptDirection:
+			if a.Direction != command.NoDirection && !answered[p] {
+				answer = string(a.Direction)
+			}
+		case screen.PromptText:
+			if a.Text != "" && !answered[p] {
+				answer = a.Text + "\n"
+			}
+		case screen.PromptYesNo:
+			// The question may follow a message on the same line: "Vlad
+			// offers 150 gold pieces for your wooden ring.  Sell it?"
+			if !answered[p] {
+				g.recordMessage()
+			}
+			// Other questions with a list of choices, like "Which
+			// ring-finger, Right or Left? [rl]", aren't ours to answer.
+			if a.Confirm && strings.Contains(text, "[yn") && !answered[p] {
+				answer = "y"
+			}
+		}
+
+		if answer == "" {
+			// Back out of the question so that the game is left in a state
+			// where the next command can be issued.
+			if err := g.send("\x1b"); err != nil {
+				return err
+			}
+			if err := g.waitIdle(true); err != nil {
+				return err
+			}
+			return &PromptError{Prompt: text}
+		}
+
+		answered[p] = true
+		if err := g.send(answer); err != nil {
+			return err
+		}
+		if err := g.waitIdle(true); err != nil {
+			return err
+		}
+	}
+}
+
+// update refreshes the model from the screen. It is called each time
+// nethack goes idle.
+func (g *Game) update() {
+	g.vtMu.Lock()
+	defer g.vtMu.Unlock()
+	s := screen.Screen(g.vt.Content)
+
+	// Errors are expected here whenever something covers the status lines.
+	// We just keep what we knew before.
+	s.ParseStatus(&g.Player)
+
+	if s.NextMenu(screen.MenuNone) == screen.MenuNone {
+		g.updateMap(s)
+	}
+}
+
+// recordMessage adds the message on the top line of the screen to the
+// current turn's events.
+func (g *Game) recordMessage() {
+	msg, _ := screen.Screen(g.vt.Content).Message()
+	g.turn = append(g.turn, screen.Sentences(msg)...)
+}
+
+// pushEvents files the messages from the current turn in Events, dropping
+// the oldest turns to stay within MaxEventLookback.
+func (g *Game) pushEvents() {
+	var t TurnEvents
+	t.add(g.turn...)
+	g.Events.PushFront(t)
+	g.turn = nil
+	for g.Events.Len() > MaxEventLookback {
+		g.Events.Remove(g.Events.Back())
+	}
 }
 
 // send tries to send s out to nethack. It keeps trying until it encounters an error
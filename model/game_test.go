@@ -0,0 +1,272 @@
+package model
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/jaguilar/nh/model/command"
+	"github.com/jaguilar/nh/model/event"
+	"github.com/jaguilar/nh/model/item"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeNethack is the game's side of a pair of pipes, standing in for a
+// nethack subprocess.
+type fakeNethack struct {
+	screen *io.PipeWriter // What nethack draws.
+	keys   bytes.Buffer   // What we typed.
+
+	// script maps keys we might type to what nethack draws in response.
+	script map[string]string
+}
+
+func (f *fakeNethack) Write(p []byte) (int, error) {
+	if out, ok := f.script[string(p)]; ok {
+		go io.WriteString(f.screen, out)
+	}
+	return f.keys.Write(p)
+}
+
+func newTestGame(t *testing.T) (*Game, *fakeNethack) {
+	r, w := io.Pipe()
+	f := &fakeNethack{screen: w}
+	g, err := NewGame(r, f, WindowSize{Y: 24, X: 80}, &Options{Timeout: 20 * time.Millisecond})
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	return g, f
+}
+
+func TestConfirm(t *testing.T) {
+	g, f := newTestGame(t)
+	defer f.screen.Close()
+
+	f.script = map[string]string{
+		"#pray\n": "\x1b[HAre you sure you want to pray? [yn] (n) ",
+		"y":       "\x1b[H\x1b[KYou begin praying to Tyr.",
+	}
+	assert.Nil(t, g.DoAction(command.Action{Command: command.Pray, Confirm: true}))
+	assert.Equal(t, "#pray\ny", f.keys.String())
+
+	f.keys.Reset()
+	_, ok := g.Do(command.Pray).(*PromptError)
+	assert.True(t, ok)
+	assert.Equal(t, "#pray\n\x1b", f.keys.String())
+}
+
+func TestDoSendsKeys(t *testing.T) {
+	g, f := newTestGame(t)
+	defer f.screen.Close()
+
+	assert.Nil(t, g.Do(command.Search))
+	assert.Nil(t, g.DoAction(command.Action{Command: command.Search, Count: 5}))
+	assert.Nil(t, g.Do(command.Pray))
+	assert.Equal(t, "s5s#pray\n", f.keys.String())
+
+	assert.NotNil(t, g.Do(command.Invalid))
+}
+
+func TestUnexpectedResume(t *testing.T) {
+	g, f := newTestGame(t)
+	defer f.screen.Close()
+
+	// Nethack draws something while we aren't waiting on it.
+	io.WriteString(f.screen, "You stop searching.")
+	for len(g.inputCommands) == 0 {
+		// Wait for the decoder to catch up.
+		time.Sleep(time.Millisecond)
+	}
+
+	assert.Equal(t, ErrUnexpectedResume, g.Do(command.Search))
+	assert.Equal(t, ErrUnexpectedResume, g.Do(command.Search))
+	assert.Equal(t, "", f.keys.String())
+
+	assert.Nil(t, g.LockWhenIdle())
+	assert.Nil(t, g.Do(command.Search))
+	g.Unlock()
+	assert.Equal(t, "s", f.keys.String())
+}
+
+// writerFunc adapts a function to io.Writer.
+type writerFunc func(p []byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) { return f(p) }
+
+func TestSettledFrameEndsWait(t *testing.T) {
+	r, w := io.Pipe()
+	defer w.Close()
+	respond := false
+	out := writerFunc(func(p []byte) (int, error) {
+		if respond {
+			// Draw the player, then leave the cursor on it.
+			go io.WriteString(w, "\x1b[2;5H@\x1b[2;5H")
+		}
+		return len(p), nil
+	})
+	g, err := NewGame(r, out, WindowSize{Y: 24, X: 80}, &Options{Timeout: 10 * time.Millisecond})
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	// From here on, only a settled screen should end a wait in a timely way.
+	g.opts.Timeout = time.Hour
+	respond = true
+
+	done := make(chan error)
+	go func() { done <- g.Do(command.Search) }()
+	select {
+	case err := <-done:
+		assert.Nil(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Do did not return after the screen settled")
+	}
+}
+
+func TestEvents(t *testing.T) {
+	g, f := newTestGame(t)
+	defer f.screen.Close()
+
+	const (
+		home      = "\x1b[H"
+		clearLine = "\x1b[K"
+	)
+	f.script = map[string]string{
+		"l":  home + "You hit the jackal.  The jackal bites!--More--",
+		"\r": home + clearLine + "You kill the jackal!",
+		"s":  home + clearLine,
+	}
+
+	assert.Nil(t, g.Do(command.East))
+	assert.Nil(t, g.Do(command.Search))
+	assert.Equal(t, "l\rs", f.keys.String())
+
+	if assert.Equal(t, 2, g.Events.Len()) {
+		assert.Empty(t, g.Events.Front().Value.E)
+		assert.Equal(t,
+			[]string{"You hit the jackal.", "The jackal bites!", "You kill the jackal!"},
+			g.Events.Back().Value.E)
+		assert.IsType(t, event.Hit{}, g.Events.Back().Value.Typed[0])
+		assert.IsType(t, event.Kill{}, g.Events.Back().Value.Typed[2])
+	}
+}
+
+func TestEventLookback(t *testing.T) {
+	g, f := newTestGame(t)
+	defer f.screen.Close()
+
+	defer func(n int) { MaxEventLookback = n }(MaxEventLookback)
+	MaxEventLookback = 2
+	for i := 0; i < 3; i++ {
+		assert.Nil(t, g.Do(command.Search))
+	}
+	assert.Equal(t, 2, g.Events.Len())
+}
+
+func TestInventory(t *testing.T) {
+	g, f := newTestGame(t)
+	defer f.screen.Close()
+
+	const clear = "\x1b[H\x1b[2J"
+	f.script = map[string]string{
+		"i":    clear + " Weapons\r\n a - a +1 long sword (weapon in hand)\r\n (1 of 2)",
+		">":    clear + " b - 2 daggers\r\n Armor\r\n c - an uncursed +0 ring mail (being worn)\r\n (2 of 2)",
+		"\x1b": clear,
+	}
+
+	assert.Nil(t, g.Do(command.Inventory))
+	assert.Equal(t, "i>\x1b", f.keys.String())
+	if assert.Len(t, g.Pack, 3) {
+		assert.Equal(t, 'a', g.Pack[0].InventoryLetter)
+		assert.Equal(t, item.Wielded, g.Pack[0].Use)
+		assert.True(t, g.Registry.ByName("long sword") == g.Pack[0].Class)
+		assert.Equal(t, item.Weapon, g.Pack[1].Class.Category)
+		assert.Equal(t, item.Armor, g.Pack[2].Class.Category)
+		assert.Equal(t, item.Worn, g.Pack[2].Use)
+		assert.Equal(t, []*item.Item{g.Pack[0], g.Pack[2]}, g.Equip)
+	}
+}
+
+func TestMenuDismissedSettles(t *testing.T) {
+	r, w := io.Pipe()
+	f := &fakeNethack{screen: w}
+	defer w.Close()
+	g, err := NewGame(r, f, WindowSize{Y: 24, X: 80}, &Options{Timeout: 400 * time.Millisecond})
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	const clear = "\x1b[H\x1b[2J"
+	f.script = map[string]string{
+		"i":    clear + " Weapons\r\n a - a +1 long sword (weapon in hand)\r\n (end)",
+		"\x1b": clear + "\x1b[3;3H@\x1b[3;3H",
+	}
+
+	// Once the menu is gone, the cursor on the player tells us we're done,
+	// without waiting out the Timeout.
+	start := time.Now()
+	assert.Nil(t, g.Do(command.Inventory))
+	assert.True(t, time.Since(start) < 200*time.Millisecond, "took %v", time.Since(start))
+}
+
+func TestIdentifyBySellOffer(t *testing.T) {
+	g, f := newTestGame(t)
+	defer f.screen.Close()
+
+	ring, err := g.Registry.Parse("a - a wooden ring")
+	assert.Nil(t, err)
+	g.Pack = []*item.Item{ring}
+	f.script = map[string]string{
+		"d": "\x1b[HWhat do you want to drop? [a or ?*] ",
+		"a": "\x1b[H\x1b[KVlad offers 150 gold pieces for your wooden ring.  Sell it? [ynaq] (y) ",
+	}
+
+	// We decline the offer, but it still tells us what the ring might be.
+	_, ok := g.DoAction(command.Action{Command: command.Drop, Letter: 'a'}).(*PromptError)
+	assert.True(t, ok)
+	assert.Equal(t, "da\x1b", f.keys.String())
+	assert.Len(t, g.Registry.Candidates("wooden ring"), 4)
+}
+
+func TestSpells(t *testing.T) {
+	g, f := newTestGame(t)
+	defer f.screen.Close()
+
+	const clear = "\x1b[H\x1b[2J"
+	f.script = map[string]string{
+		"Z": clear + " Choose which spell to cast\r\n\r\n" +
+			"     Name                 Level  Category     Fail\r\n" +
+			" a - force bolt             1    attack         0%\r\n" +
+			" b - sleep                  1*   enchantment   13%\r\n" +
+			" (end)",
+		"\x1b": clear,
+		"a":    clear + "In what direction?",
+		"l":    clear,
+	}
+
+	assert.Nil(t, g.Do(command.Cast))
+	assert.Equal(t, "Z\x1b", f.keys.String())
+	if assert.Len(t, g.Spells, 2) {
+		assert.Equal(t, "force bolt", g.Spells[0].Name)
+		assert.True(t, g.Spells[1].Forgotten)
+	}
+
+	f.keys.Reset()
+	g.Spells = nil
+	assert.Nil(t, g.DoAction(command.Action{Command: command.Cast, Letter: 'a', Direction: command.DirEast}))
+	assert.Equal(t, "Zal", f.keys.String())
+	assert.Len(t, g.Spells, 2)
+}
+
+func TestIdentifyByPrice(t *testing.T) {
+	g, f := newTestGame(t)
+	defer f.screen.Close()
+
+	g.Cha = 11
+	f.script = map[string]string{
+		"s": "\x1b[HFor you, esteemed sir; only 300 zorkmids for this wooden ring.",
+	}
+	assert.Nil(t, g.Do(command.Search))
+	assert.Len(t, g.Registry.Candidates("wooden ring"), 4)
+}
@@ -0,0 +1,145 @@
+package model
+
+import (
+	"time"
+
+	"github.com/jaguilar/nh/model/command"
+	"github.com/jaguilar/nh/model/internal/screen"
+)
+
+// IdleDetector decides whether nethack has finished drawing its response to
+// a command and is waiting for input.
+type IdleDetector interface {
+	// Settled is called each time nethack draws something. content is the
+	// screen in row-major order, (y, x) is the cursor position, and last is
+	// the command that we most recently issued.
+	//
+	// Returning true doesn't end the wait immediately. The Game still waits
+	// for Options.Settle to make sure nethack isn't about to draw more.
+	Settled(content [][]rune, y, x int, last command.Command) bool
+}
+
+// CursorIdle is the default IdleDetector. It predicts where nethack leaves
+// the cursor when it's waiting for input: at the end of the top line, on the
+// player, or after a menu's "(end)" or "(x of y)".
+type CursorIdle struct{}
+
+// Settled is part of the IdleDetector interface.
+func (CursorIdle) Settled(content [][]rune, y, x int, last command.Command) bool {
+	s := screen.Screen(content)
+	if s.CursorAtTopLineEnd(y, x) || s.CursorAfterMenuEnd(y, x) {
+		return true
+	}
+	if opensMenu(last) {
+		// The cursor passes over the player on its way to drawing the menu,
+		// so the player is not a reliable signal for these commands.
+		return false
+	}
+	return s.CursorOnPlayer(y, x)
+}
+
+// opensMenu returns whether c normally displays a menu rather than returning
+// to the map.
+func opensMenu(c command.Command) bool {
+	switch c {
+	case command.Inventory, command.Discoveries, command.Enhance, command.Cast, command.DropMany:
+		return true
+	default:
+		return false
+	}
+}
+
+// Options control how a Game talks to nethack. The zero value of each field
+// selects a reasonable default.
+type Options struct {
+	// Idle decides when nethack has finished drawing. Default: CursorIdle.
+	Idle IdleDetector
+
+	// Settle is how long the screen must stay unchanged after Idle reports
+	// that it's settled before we consider nethack idle. Default: 2ms.
+	Settle time.Duration
+
+	// Symset is the symset nethack is configured to draw the map with.
+	// Default: DefaultSymset.
+	Symset
+
+	// Timeout is how long the screen must stay unchanged before we consider
+	// nethack idle when Idle doesn't think the screen is settled. This is the
+	// fallback for screens we don't understand, so it should be long enough to
+	// cover the latency of your connection to nethack. Default: 200ms.
+	Timeout time.Duration
+}
+
+// withDefaults returns a copy of o with the defaults filled in. o may be nil.
+func (o *Options) withDefaults() Options {
+	var d Options
+	if o != nil {
+		d = *o
+	}
+	if d.Idle == nil {
+		d.Idle = CursorIdle{}
+	}
+	if d.Settle == 0 {
+		d.Settle = 2 * time.Millisecond
+	}
+	if d.Timeout == 0 {
+		d.Timeout = 200 * time.Millisecond
+	}
+	return d
+}
+
+// settled asks the IdleDetector whether the current screen is settled.
+func (g *Game) settled() bool {
+	g.vtMu.Lock()
+	defer g.vtMu.Unlock()
+	return g.opts.Idle.Settled(g.vt.Content, g.vt.Cursor.Y, g.vt.Cursor.X, g.lastCmd)
+}
+
+// waitIdle waits until a nethack frame has finished drawing. If expectOutput
+// is true, we've just sent nethack some keys, so the screen as it stands is
+// stale and we must wait for the response before trusting the IdleDetector.
+func (g *Game) waitIdle(expectOutput bool) error {
+	timeout := func() time.Duration {
+		if g.settled() {
+			return g.opts.Settle
+		}
+		return g.opts.Timeout
+	}
+
+	var timer *time.Timer
+	if expectOutput {
+		timer = time.NewTimer(g.opts.Timeout)
+	} else {
+		timer = time.NewTimer(timeout())
+	}
+	defer timer.Stop()
+
+	for {
+		select {
+		case cmd, ok := <-g.inputCommands:
+			if !ok {
+				// Only way this can happen is io.EOF from the input channel.
+				return ErrGameOver
+			}
+			if err := g.process(cmd); err != nil {
+				return err
+			}
+		case err, ok := <-g.inputErrs:
+			if !ok {
+				return ErrGameOver
+			}
+			return err
+		case <-timer.C:
+			g.update()
+			return nil
+		}
+
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		timer.Reset(timeout())
+	}
+}
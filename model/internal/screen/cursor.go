@@ -0,0 +1,49 @@
+package screen
+
+import (
+	"regexp"
+	"strings"
+)
+
+// The functions in this file tell us whether the cursor is resting somewhere
+// nethack leaves it when it is waiting for input. Nethack has three such
+// places: at the end of the top line (after a message, a --More--, or a
+// prompt), on the player character, and just after the "(end)" or "(x of y)"
+// that terminates a menu.
+
+// CursorAtTopLineEnd reports whether the cursor is just past the text on the
+// top line. Prompts are followed by a space, so we allow the cursor to be one
+// column further right than the text.
+func (s Screen) CursorAtTopLineEnd(y, x int) bool {
+	if y != 0 {
+		return false
+	}
+	top := strings.TrimRight(string(s[0]), " ")
+	if top == "" {
+		return false
+	}
+	n := len([]rune(top))
+	return x == n || x == n+1
+}
+
+// CursorOnPlayer reports whether the cursor is on an '@' in the map area of
+// the screen. We can't tell the player apart from other humans from this
+// alone, but nethack never leaves the cursor on another monster.
+func (s Screen) CursorOnPlayer(y, x int) bool {
+	if y < 1 || y >= len(s)-2 || x < 0 || x >= len(s[y]) {
+		return false
+	}
+	return s[y][x] == '@'
+}
+
+var menuEndRe = regexp.MustCompile(`\((?:end|\d+ of \d+)\) ?$`)
+
+// CursorAfterMenuEnd reports whether the cursor is just past a menu
+// terminator on its row.
+func (s Screen) CursorAfterMenuEnd(y, x int) bool {
+	if y < 0 || y >= len(s) || x < 0 || x > len(s[y]) {
+		return false
+	}
+	before := string(s[y][:x])
+	return menuEndRe.MatchString(before)
+}
@@ -0,0 +1,33 @@
+package screen
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCursorAtTopLineEnd(t *testing.T) {
+	s := screenOf("What do you want to eat? [fg or ?*] ")
+	assert.True(t, s.CursorAtTopLineEnd(0, 35))
+	assert.True(t, s.CursorAtTopLineEnd(0, 36))
+	assert.False(t, s.CursorAtTopLineEnd(0, 10))
+	assert.False(t, s.CursorAtTopLineEnd(1, 35))
+	assert.False(t, screenOf("").CursorAtTopLineEnd(0, 0))
+}
+
+func TestCursorOnPlayer(t *testing.T) {
+	s := screenOf("", "  |..@..|")
+	assert.True(t, s.CursorOnPlayer(1, 5))
+	assert.False(t, s.CursorOnPlayer(1, 4))
+	assert.False(t, s.CursorOnPlayer(0, 5))
+}
+
+func TestCursorAfterMenuEnd(t *testing.T) {
+	s := screenOf("", "", "a - a +1 long sword (weapon in hand)", "(end) ")
+	assert.True(t, s.CursorAfterMenuEnd(3, 6))
+	assert.True(t, s.CursorAfterMenuEnd(3, 5))
+	assert.False(t, s.CursorAfterMenuEnd(3, 2))
+
+	s = screenOf("", "", "(1 of 2)")
+	assert.True(t, s.CursorAfterMenuEnd(2, 8))
+}
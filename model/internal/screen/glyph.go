@@ -0,0 +1,232 @@
+package screen
+
+import (
+	"unicode"
+
+	"github.com/jaguilar/nh/model/item"
+	"github.com/jaguilar/nh/model/square"
+)
+
+// The map occupies the rows between the top line and the status lines.
+const (
+	MapTop    = 1
+	MapHeight = 21
+	MapWidth  = 80
+)
+
+// CellKind is what a map cell shows, as far as we can tell from its glyph.
+type CellKind int
+
+const (
+	// CellBlank - nothing is shown. Unexplored, solid rock, or dark floor
+	// we don't remember.
+	CellBlank CellKind = iota
+
+	// CellTerrain - a dungeon feature with nothing on top of it.
+	CellTerrain
+
+	// CellObject - an object, which may be remembered rather than seen.
+	CellObject
+
+	// CellMonster - a monster (or a remembered unseen monster, 'I').
+	CellMonster
+
+	// CellPlayer - the player character.
+	CellPlayer
+)
+
+// Cell is our interpretation of a single map glyph.
+type Cell struct {
+	Kind CellKind
+
+	// Rune is the glyph itself. For monsters, this is the monster class.
+	Rune rune
+
+	// Terrain is set if Kind is CellTerrain.
+	Terrain square.Terrain
+
+	// Category is set if Kind is CellObject.
+	Category item.Category
+}
+
+// Symset maps glyphs to their meaning. Glyphs that aren't in the Symset are
+// taken to be monsters if they're letters or one of the other monster class
+// symbols, and are otherwise blank.
+//
+// Many glyphs are ambiguous. '#' is a corridor, but also a tree, a sink or
+// iron bars. We map each glyph to its most common meaning.
+type Symset map[rune]Cell
+
+func terrain(t square.Terrain) Cell { return Cell{Kind: CellTerrain, Terrain: t} }
+func object(c item.Category) Cell   { return Cell{Kind: CellObject, Category: c} }
+
+// withRunes fills in the Rune of each Cell in s.
+func withRunes(s Symset) Symset {
+	for r, c := range s {
+		c.Rune = r
+		s[r] = c
+	}
+	return s
+}
+
+// objectSyms are the object class symbols, which are the same in every
+// symset.
+var objectSyms = Symset{
+	')': object(item.Weapon),
+	'[': object(item.Armor),
+	'%': object(item.Comestible),
+	'?': object(item.Scroll),
+	'/': object(item.Wand),
+	'=': object(item.Ring),
+	'!': object(item.Potion),
+	'(': object(item.Tool),
+	'"': object(item.Amulet),
+	'*': object(item.Gem),
+	'$': object(item.Coins),
+	'`': object(item.Boulder),
+	'0': object(item.HeavyIronBall),
+}
+
+// merge combines Symsets. Later sets override earlier ones.
+func merge(sets ...Symset) Symset {
+	out := make(Symset)
+	for _, s := range sets {
+		for r, c := range s {
+			out[r] = c
+		}
+	}
+	return withRunes(out)
+}
+
+var (
+	// DefaultSymset is nethack's default ASCII symset.
+	DefaultSymset = merge(objectSyms, Symset{
+		'|':  terrain(square.Wall),
+		'-':  terrain(square.Wall),
+		'.':  terrain(square.Floor),
+		'#':  terrain(square.Corridor),
+		'+':  terrain(square.DoorClosed),
+		'<':  terrain(square.StaircaseUp),
+		'>':  terrain(square.StaircaseDown),
+		'_':  terrain(square.Altar),
+		'{':  terrain(square.Fountain),
+		'}':  terrain(square.Water),
+		'\\': terrain(square.Throne),
+		'^':  terrain(square.Trap),
+	})
+
+	// boxSyms are the glyphs that the IBM and DEC symsets have in common,
+	// as they appear once the terminal has translated them to Unicode.
+	boxSyms = Symset{
+		'│':  terrain(square.Wall),
+		'─':  terrain(square.Wall),
+		'┌':  terrain(square.Wall),
+		'┐':  terrain(square.Wall),
+		'└':  terrain(square.Wall),
+		'┘':  terrain(square.Wall),
+		'┼':  terrain(square.Wall),
+		'┴':  terrain(square.Wall),
+		'┬':  terrain(square.Wall),
+		'┤':  terrain(square.Wall),
+		'├':  terrain(square.Wall),
+		'·':  terrain(square.Floor),
+		'<':  terrain(square.StaircaseUp),
+		'>':  terrain(square.StaircaseDown),
+		'+':  terrain(square.DoorClosed),
+		'_':  terrain(square.Altar),
+		'\\': terrain(square.Throne),
+		'^':  terrain(square.Trap),
+	}
+
+	// IBMSymset is the IBMgraphics symset.
+	IBMSymset = merge(objectSyms, boxSyms, Symset{
+		'░': terrain(square.Corridor),
+		'▒': terrain(square.Corridor),
+		'■': terrain(square.DoorOpenHoriz),
+		'⌠': terrain(square.Fountain),
+		'≈': terrain(square.Water),
+		'♣': terrain(square.Tree),
+		'#': terrain(square.Corridor),
+	})
+
+	// DECSymset is the DECgraphics symset. It only works if the terminal
+	// translates the DEC line drawing characters to Unicode before we see
+	// them. Otherwise they come through as lowercase letters and look like
+	// monsters.
+	DECSymset = merge(objectSyms, boxSyms, Symset{
+		'▒': terrain(square.Corridor),
+		'#': terrain(square.Corridor),
+		'{': terrain(square.Fountain),
+		'}': terrain(square.Water),
+		'≈': terrain(square.Water),
+	})
+)
+
+// isMonsterSym returns whether r is a monster class symbol.
+func isMonsterSym(r rune) bool {
+	if r < unicode.MaxASCII && unicode.IsLetter(r) {
+		return true
+	}
+	switch r {
+	case '@', '&', ';', ':', '\'', '~':
+		return true
+	}
+	return false
+}
+
+// Map interprets each cell of the map area of the screen using syms. (y, x)
+// is the position of the cursor, which is how we find the player. The result
+// is indexed [row][column], with row 0 being the top row of the map (not the
+// top row of the screen).
+func (s Screen) Map(syms Symset, y, x int) [][]Cell {
+	out := make([][]Cell, MapHeight)
+	for row := range out {
+		out[row] = make([]Cell, MapWidth)
+		line := s[MapTop+row]
+		for col := 0; col < MapWidth && col < len(line); col++ {
+			r := line[col]
+			c, ok := syms[r]
+			switch {
+			case ok:
+			case r == '@' && MapTop+row == y && col == x:
+				c = Cell{Kind: CellPlayer, Rune: r}
+			case isMonsterSym(r):
+				c = Cell{Kind: CellMonster, Rune: r}
+			default:
+				c = Cell{Kind: CellBlank, Rune: r}
+			}
+			out[row][col] = c
+		}
+	}
+
+	// '+' is both a closed door and a spellbook. Doors are always set into a
+	// wall, so a '+' that isn't between two walls must be a spellbook.
+	for row := range out {
+		for col, c := range out[row] {
+			if c.Kind == CellTerrain && c.Terrain == square.DoorClosed && !betweenWalls(out, row, col) {
+				out[row][col] = Cell{Kind: CellObject, Rune: c.Rune, Category: item.Spellbook}
+			}
+		}
+	}
+	return out
+}
+
+// betweenWalls returns whether the cell at (row, col) has walls (or doors)
+// on both sides, either horizontally or vertically.
+func betweenWalls(m [][]Cell, row, col int) bool {
+	wallish := func(row, col int) bool {
+		if row < 0 || row >= len(m) || col < 0 || col >= len(m[row]) {
+			return false
+		}
+		c := m[row][col]
+		if c.Kind != CellTerrain {
+			return false
+		}
+		switch c.Terrain {
+		case square.Wall, square.DoorClosed, square.DoorOpenHoriz, square.DoorOpenVert, square.DoorOpenDestroyed:
+			return true
+		}
+		return false
+	}
+	return (wallish(row, col-1) && wallish(row, col+1)) || (wallish(row-1, col) && wallish(row+1, col))
+}
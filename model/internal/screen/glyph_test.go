@@ -0,0 +1,38 @@
+package screen
+
+import (
+	"testing"
+
+	"github.com/jaguilar/nh/model/item"
+	"github.com/jaguilar/nh/model/square"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMap(t *testing.T) {
+	s := screenOf(
+		"",
+		" -----+---",
+		" |.@.d)..|",
+		" |.+.<..@#",
+		" ---------",
+	)
+	m := s.Map(DefaultSymset, 2, 3)
+	assert.Equal(t, CellBlank, m[0][0].Kind)
+	assert.Equal(t, Cell{Kind: CellTerrain, Rune: '-', Terrain: square.Wall}, m[0][1])
+	assert.Equal(t, square.DoorClosed, m[0][6].Terrain, "door set in a wall")
+	assert.Equal(t, Cell{Kind: CellPlayer, Rune: '@'}, m[1][3])
+	assert.Equal(t, Cell{Kind: CellMonster, Rune: 'd'}, m[1][5])
+	assert.Equal(t, Cell{Kind: CellObject, Rune: ')', Category: item.Weapon}, m[1][6])
+	assert.Equal(t, Cell{Kind: CellObject, Rune: '+', Category: item.Spellbook}, m[2][3], "spellbook on the floor")
+	assert.Equal(t, square.StaircaseUp, m[2][5].Terrain)
+	assert.Equal(t, Cell{Kind: CellMonster, Rune: '@'}, m[2][8], "a human that isn't us")
+	assert.Equal(t, square.Corridor, m[2][9].Terrain)
+}
+
+func TestMapIBM(t *testing.T) {
+	s := screenOf("", " ┌──┐", " │·@│", " └──┘")
+	m := s.Map(IBMSymset, 2, 3)
+	assert.Equal(t, square.Wall, m[0][1].Terrain)
+	assert.Equal(t, square.Floor, m[1][2].Terrain)
+	assert.Equal(t, CellPlayer, m[1][3].Kind)
+}
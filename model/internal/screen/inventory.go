@@ -0,0 +1,125 @@
+package screen
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/jaguilar/nh/model/item"
+)
+
+// categoryHeadings are the headings nethack groups the inventory under.
+var categoryHeadings = map[string]item.Category{
+	"Coins":            item.Coins,
+	"Amulets":          item.Amulet,
+	"Weapons":          item.Weapon,
+	"Armor":            item.Armor,
+	"Comestibles":      item.Comestible,
+	"Scrolls":          item.Scroll,
+	"Spellbooks":       item.Spellbook,
+	"Potions":          item.Potion,
+	"Rings":            item.Ring,
+	"Wands":            item.Wand,
+	"Tools":            item.Tool,
+	"Gems":             item.Gem,
+	"Gems/Stones":      item.Gem,
+	"Boulders/Statues": item.Boulder,
+	"Iron balls":       item.HeavyIronBall,
+	"Chains":           item.IronChain,
+}
+
+var (
+	// itemLineRe matches a menu line with an inventory letter.
+	itemLineRe = regexp.MustCompile(`^[a-zA-Z$#] - `)
+
+	// menuPageRe matches the line that ends each page of a menu.
+	menuPageRe = regexp.MustCompile(`^\((?:end|(\d+) of (\d+))\)$`)
+)
+
+/*
+menuLines returns the lines of the menu on the screen, with the part of the
+screen to their left cut off, up to but not including the line that ends the
+page. page and pages are taken from that line: "(2 of 3)" is page 2 of 3,
+and "(end)" is page 1 of 1. If there's no end line, pages is 0.
+
+The menu's left edge is the first column of the top line that isn't blank.
+In the short layout the menu is drawn over the right of the map, and the
+map is still visible to the left:
+
+	              ·Weapons
+	dungeon line 1·a - a +1 long sword (weapon in hand)
+	dungeon line 2·(end)
+
+In the long layout, the left edge is column 1. Either way, each line of the
+menu starts at the left edge.
+*/
+func (s Screen) menuLines() (lines []string, page, pages int) {
+	top := string(s[0])
+	left := len(top) - len(strings.TrimLeft(top, " "))
+	for _, row := range s {
+		if left >= len(row) {
+			lines = append(lines, "")
+			continue
+		}
+		l := strings.TrimRight(string(row[left:]), " ")
+		if m := menuPageRe.FindStringSubmatch(l); m != nil {
+			if m[1] == "" {
+				return lines, 1, 1
+			}
+			page, _ = strconv.Atoi(m[1])
+			pages, _ = strconv.Atoi(m[2])
+			return lines, page, pages
+		}
+		lines = append(lines, l)
+	}
+	return lines, 0, 0
+}
+
+// MenuPage returns which page of a menu is shown, and how many pages there
+// are. If no menu is shown, pages is 0.
+func (s Screen) MenuPage() (page, pages int) {
+	_, page, pages = s.menuLines()
+	return page, pages
+}
+
+// ParseItems parses a list of items on the screen. It returns items it was
+// able to parse successfully separately from those it could not parse.
+// In the long run, we should get to the point where we never return any
+// errors.
+//
+// Items get their Classes from r, which may be nil (see item.Parse). An
+// item whose class we can't tell is given the Category of the heading it's
+// listed under. Only the page of the menu that's on the screen is parsed.
+// Headings aren't repeated when their items continue onto the next page, so
+// cat is the Category of the items above the page's first heading.
+func (s Screen) ParseItems(r *item.Registry, cat item.Category) (items []*item.Item, errs []string) {
+	lines, _, _ := s.menuLines()
+	for _, l := range lines {
+		if l == "" {
+			continue
+		}
+		if c, ok := categoryHeadings[l]; ok {
+			cat = c
+			continue
+		}
+		if !itemLineRe.MatchString(l) {
+			errs = append(errs, l)
+			continue
+		}
+		var (
+			i   *item.Item
+			err error
+		)
+		if r != nil {
+			i, err = r.ParseIn(l, cat)
+		} else {
+			i, err = item.ParseIn(l, cat)
+		}
+		if err != nil {
+			errs = append(errs, l)
+			continue
+		}
+		items = append(items, i)
+	}
+	return items, errs
+}
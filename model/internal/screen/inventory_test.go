@@ -0,0 +1,85 @@
+package screen
+
+import (
+	"testing"
+
+	"github.com/jaguilar/nh/model/item"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseItemsShort(t *testing.T) {
+	s := screenOf(
+		"                          Weapons",
+		" ------------             a - a +1 long sword (weapon in hand)",
+		" |..........|             b - 2 +0 daggers (alternate weapon; not wielded)",
+		" |....@.....|             Armor",
+		" |..........|             c - an uncursed +0 ring mail (being worn)",
+		" ------------             Comestibles",
+		"                          d - 3 food rations",
+		"                          (end)",
+		"                          e - junk below the menu",
+	)
+	assert.Equal(t, MenuInv, s.NextMenu(MenuNone))
+	page, pages := s.MenuPage()
+	assert.Equal(t, 1, page)
+	assert.Equal(t, 1, pages)
+
+	items, errs := s.ParseItems(nil, 0)
+	assert.Empty(t, errs)
+	if assert.Len(t, items, 4) {
+		assert.Equal(t, 'a', items[0].InventoryLetter)
+		assert.Equal(t, item.Weapon, items[0].Class.Category)
+		assert.Equal(t, item.Wielded, items[0].Use)
+		assert.Equal(t, item.Alternate, items[1].Use)
+		assert.Equal(t, 2, items[1].Stack)
+		assert.Equal(t, item.Armor, items[2].Class.Category)
+		assert.Equal(t, item.Worn, items[2].Use)
+		assert.Equal(t, item.Comestible, items[3].Class.Category)
+		assert.Equal(t, 3, items[3].Stack)
+	}
+}
+
+func TestParseItemsLong(t *testing.T) {
+	s := screenOf(
+		" Coins",
+		" $ - 40 gold pieces",
+		" Scrolls",
+		" f - 2 uncursed scrolls of identify",
+		" g - a scroll labeled FOOBIE BLETCH",
+		" ??? something we can't parse",
+		" (1 of 2)",
+	)
+	page, pages := s.MenuPage()
+	assert.Equal(t, 1, page)
+	assert.Equal(t, 2, pages)
+
+	items, errs := s.ParseItems(nil, 0)
+	assert.Equal(t, []string{"??? something we can't parse"}, errs)
+	if assert.Len(t, items, 3) {
+		assert.Equal(t, item.Coins, items[0].Class.Category)
+		assert.Equal(t, 'g', items[2].InventoryLetter)
+		assert.Equal(t, item.Scroll, items[2].Class.Category)
+	}
+}
+
+func TestParseItemsContinued(t *testing.T) {
+	// The second page of a menu whose Weapons continue from the first. We
+	// can only tell what the unknown artifacts are from their headings.
+	s := screenOf(
+		" h - the Foo (weapon in hand)",
+		" Tools",
+		" i - the Bar",
+		" (2 of 2)",
+	)
+	items, errs := s.ParseItems(nil, item.Weapon)
+	assert.Empty(t, errs)
+	if assert.Len(t, items, 2) {
+		assert.Equal(t, item.Weapon, items[0].Class.Category)
+		assert.Equal(t, item.Tool, items[1].Class.Category)
+	}
+}
+
+func TestMenuPageNoMenu(t *testing.T) {
+	_, pages := screenOf("You see here a dagger.").MenuPage()
+	assert.Equal(t, 0, pages)
+}
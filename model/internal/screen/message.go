@@ -0,0 +1,62 @@
+package screen
+
+import (
+	"strings"
+	"unicode"
+)
+
+const moreMarker = "--More--"
+
+// Message returns the message shown on the top line of the screen, if any.
+// more is true if the message is followed by a --More--, meaning nethack
+// has further messages to show once this one is acknowledged.
+//
+// Menus also use the top line, but they always leave its first column blank
+// (see NextMenu). In that case there is no message.
+func (s Screen) Message() (msg string, more bool) {
+	top := strings.TrimRight(string(s[0]), " ")
+	if top == "" || top[0] == ' ' {
+		return "", false
+	}
+	if strings.HasSuffix(top, moreMarker) {
+		return strings.TrimSpace(strings.TrimSuffix(top, moreMarker)), true
+	}
+	return top, false
+}
+
+// Sentences splits the messages on a top line into individual sentences.
+// Nethack separates the messages it puts on one line with two spaces. A
+// single message may also consist of several sentences ("Hello Agent, welcome
+// to NetHack! You are a neutral female human Valkyrie."). We split after
+// sentence-ending punctuation if the next word starts with a capital letter,
+// which avoids splitting things like "Dlvl. 3" or ellipses mid-sentence.
+func Sentences(msg string) []string {
+	var out []string
+	for _, m := range strings.Split(msg, "  ") {
+		m = strings.TrimSpace(m)
+		if m == "" {
+			continue
+		}
+		out = append(out, splitSentences(m)...)
+	}
+	return out
+}
+
+func splitSentences(m string) []string {
+	var out []string
+	r := []rune(m)
+	start := 0
+	for i := 0; i+2 < len(r); i++ {
+		if !strings.ContainsRune(".!?", r[i]) || r[i+1] != ' ' || !unicode.IsUpper(r[i+2]) {
+			continue
+		}
+		if r[i] == '.' && i > 0 && r[i-1] == '.' {
+			// An ellipsis: "You hear the footsteps of a guard on patrol... "
+			// continues with the rest of the sentence.
+			continue
+		}
+		out = append(out, string(r[start:i+1]))
+		start = i + 2
+	}
+	return append(out, string(r[start:]))
+}
@@ -0,0 +1,41 @@
+package screen
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMessage(t *testing.T) {
+	for _, tc := range []struct {
+		top, msg string
+		more     bool
+	}{
+		{"", "", false},
+		{"You hit the jackal.", "You hit the jackal.", false},
+		{"You hit the jackal.  The jackal bites!--More--", "You hit the jackal.  The jackal bites!", true},
+		{"                                   Weapons", "", false},
+	} {
+		msg, more := screenOf(tc.top).Message()
+		assert.Equal(t, tc.msg, msg)
+		assert.Equal(t, tc.more, more)
+	}
+}
+
+func TestSentences(t *testing.T) {
+	for _, tc := range []struct {
+		msg  string
+		want []string
+	}{
+		{"", nil},
+		{"You hit the jackal.  The jackal bites!", []string{"You hit the jackal.", "The jackal bites!"}},
+		{
+			"Hello Agent, welcome to NetHack! You are a neutral female human Valkyrie.",
+			[]string{"Hello Agent, welcome to NetHack!", "You are a neutral female human Valkyrie."},
+		},
+		{"You see here a +1 long sword.", []string{"You see here a +1 long sword."}},
+		{"Things that are here: ... Never mind.", []string{"Things that are here: ... Never mind."}},
+	} {
+		assert.Equal(t, tc.want, Sentences(tc.msg), "%q", tc.msg)
+	}
+}
@@ -1,10 +1,7 @@
 package screen
 
 import (
-	"regexp"
 	"strings"
-
-	"github.com/jaguilar/nh/model/item"
 )
 
 // Screen is the fundamental type we parse. It represents a nethack screen.
@@ -96,7 +93,12 @@ func (s Screen) NextMenu(last MenuFormat) MenuFormat {
 	}
 
 	// Ok, so it looks like we're trying to transition to a menu!
-	if invListRegexp.MatchString(top) {
+	// The inventory always starts with a category heading. We only recognize
+	// the full "i" inventory this way. Other item menus start with a question
+	// instead. For example, if you do "d*", the top line will say,
+	// "What would you like to drop?" For the time being, we treat such cases
+	// as MenuUnknown.
+	if _, ok := categoryHeadings[strings.TrimSpace(top)]; ok {
 		return MenuInv
 	}
 	if strings.Contains(top, "Pick a skill to advance") || strings.Contains(top, "current skills") {
@@ -108,63 +110,3 @@ func (s Screen) NextMenu(last MenuFormat) MenuFormat {
 
 	return MenuUnknown
 }
-
-var (
-	// invListRegexp will match the top line of the inventory if it's just the
-	// "i" inventory. Otherwise, it may not, depending on how the inventory was
-	// accessed. For example, if you do "d*", the top line will say,
-	// "drop what?" For the time being, we will treat such cases as mcUnhandled.
-	invListRegexp = regexp.MustCompile(`
-		(Amulets)|(Weapons)|(Armor)|
-		(Potions)|(Scrolls)|(Wands)|
-		(Rings)|(Spellbooks)|(Gems)|
-		(Tools)|(Boulders/Statues)|
-		(Comestibles)|(Iron balls)`)
-)
-
-// ParseItems parses a list of items on the screen. It returns items it was
-// able to parse successfully separately from those it could not parse.
-// In the long run, we should get to the point where we never return any
-// errors.
-func (s Screen) ParseItems() (items []*item.Item, errs []string) {
-	/* istrings := s.itemStrings()
-
-	for _, s := range istrings {
-		i, err := item.Parse(s)
-
-	}*/
-
-	return
-}
-
-/*
-func (g *Game) parseEvents() error {
-	var buf bytes.Buffer
-	for {
-		eventopine := string(g.vt.Content[0])
-		eventopine = strings.TrimRight(eventopine, " ")
-		if eventopine == "" {
-			break
-		}
-		split := strings.Split(eventopine, "--More--")
-		buf.WriteString(split[0]) // Everything before the "--More--" is part of the event.
-		if len(split) == 1 {      // Nothing more to show this turn.
-			break
-		}
-	}
-
-	// Currently we interpret no events. We just split after the period in each sentence
-	// and append that to the events list. Later, we will begin interpreting these events.
-	sentences := strings.SplitAfter(buf.String(), ".")
-	for i, s := range sentences {
-		sentences[i] = strings.TrimLeft(s, " ")
-	}
-	g.Events.PushFront(TurnEvents{E: sentences})
-
-	return nil
-}
-
-func (g *Game) parseMenu() error {
-	return nil
-}
-*/
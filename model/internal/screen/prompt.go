@@ -0,0 +1,69 @@
+package screen
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Prompt is a kind of question nethack can ask on the top line of the screen.
+type Prompt int
+
+const (
+	// PromptNone - nethack is not asking anything.
+	PromptNone Prompt = iota
+
+	// PromptMore - nethack is waiting for us to acknowledge a --More--.
+	PromptMore
+
+	// PromptItem - nethack wants an inventory letter.
+	// "What do you want to eat? [fg or ?*]"
+	PromptItem
+
+	// PromptDirection - nethack wants a direction. "In what direction?"
+	PromptDirection
+
+	// PromptText - nethack wants a line of text, terminated by a newline.
+	// "What do you want to write in the dust here?"
+	PromptText
+
+	// PromptYesNo - nethack wants a single character from a short list of
+	// choices. "Really attack the guard? [yn] (n)"
+	PromptYesNo
+
+	// PromptUnknown - the top line ends like a question, but it's not one
+	// we recognize.
+	PromptUnknown
+)
+
+var (
+	itemPromptRe  = regexp.MustCompile(`^What do you want to [^?]*\? \[[^\]]*\]$`)
+	yesNoPromptRe = regexp.MustCompile(`\? \[[a-zA-Z#]+\](?: \(.\))?$`)
+	dirPromptRe   = regexp.MustCompile(`^In what direction[^?]*\?$`)
+	textPromptRe  = regexp.MustCompile(`(?:^What do you want to [^?]*\?$)|(?:^Call [^:]*:$)|(?:^For what do you wish\?$)`)
+)
+
+// Prompt returns the kind of prompt being shown on the top line, along with
+// the text of the top line.
+func (s Screen) Prompt() (Prompt, string) {
+	top := strings.TrimRight(string(s[0]), " ")
+	switch {
+	case top == "" || top[0] == ' ':
+		// Either nothing is shown, or we're looking at a menu (see NextMenu).
+		return PromptNone, strings.TrimSpace(top)
+	case strings.HasSuffix(top, moreMarker):
+		return PromptMore, top
+	case itemPromptRe.MatchString(top):
+		return PromptItem, top
+	case yesNoPromptRe.MatchString(top):
+		return PromptYesNo, top
+	case dirPromptRe.MatchString(top):
+		return PromptDirection, top
+	case textPromptRe.MatchString(top):
+		return PromptText, top
+	case strings.HasSuffix(top, "?") || strings.HasSuffix(top, "]") || strings.HasSuffix(top, ":"):
+		return PromptUnknown, top
+	default:
+		// An ordinary message.
+		return PromptNone, top
+	}
+}
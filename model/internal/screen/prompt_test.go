@@ -0,0 +1,46 @@
+package screen
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// screenOf makes an 80 column Screen with the given lines at the top.
+func screenOf(lines ...string) Screen {
+	s := make(Screen, 24)
+	for i := range s {
+		var l string
+		if i < len(lines) {
+			l = lines[i]
+		}
+		s[i] = []rune(l + strings.Repeat(" ", 80-len([]rune(l))))
+	}
+	return s
+}
+
+func TestPrompt(t *testing.T) {
+	for _, tc := range []struct {
+		top string
+		Prompt
+	}{
+		{"", PromptNone},
+		{"You hit the jackal.", PromptNone},
+		{"You hit the jackal.  The jackal bites!--More--", PromptMore},
+		{"What do you want to eat? [fg or ?*]", PromptItem},
+		{"What do you want to write with? [- abc or ?*]", PromptItem},
+		{"In what direction?", PromptDirection},
+		{"What do you want to write in the dust here?", PromptText},
+		{"Call a bubbly potion:", PromptText},
+		{"For what do you wish?", PromptText},
+		{"Really attack the watchman? [yn] (n)", PromptYesNo},
+		{"Do you want to add to the current engraving? [ynq] (y)", PromptYesNo},
+		{"Which ring-finger, Right or Left? [rl]", PromptYesNo},
+		{"Where do you want to jump?", PromptUnknown},
+		{"                                   Pick a skill to advance:", PromptNone},
+	} {
+		p, _ := screenOf(tc.top).Prompt()
+		assert.Equal(t, tc.Prompt, p, "%q", tc.top)
+	}
+}
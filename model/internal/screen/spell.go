@@ -0,0 +1,48 @@
+package screen
+
+import (
+	"regexp"
+	"strconv"
+
+	"github.com/jaguilar/nh/model/item"
+	"github.com/jaguilar/nh/model/pc"
+)
+
+// spellLineRe matches a line of the spell menu, as nethack 3.4.3 shows it:
+//
+//	a - force bolt             1    attack         0%
+//
+// or as 3.6 does, with a column for how well the spell is remembered:
+//
+//	a - force bolt             1   attack         0%  76%-100%
+//
+// A "*" after the level marks a forgotten spell in 3.4.3. In 3.6, its
+// retention is "(gone)".
+var spellLineRe = regexp.MustCompile(`^([a-zA-Z]) - (.+?) +(\d+)(\*?) +([a-z]+) +(\d+)%(?: +(\(gone\)|\d+%(?:-\d+%)?))?$`)
+
+// ParseSpells parses the spell menu on the screen. Lines it can't parse are
+// returned separately. The menu's title and column headings are skipped.
+func (s Screen) ParseSpells() (spells []pc.Spell, errs []string) {
+	lines, _, _ := s.menuLines()
+	for k, l := range lines {
+		if l == "" || k < 3 && !itemLineRe.MatchString(l) {
+			continue
+		}
+		m := spellLineRe.FindStringSubmatch(l)
+		if m == nil {
+			errs = append(errs, l)
+			continue
+		}
+		level, _ := strconv.Atoi(m[3])
+		fail, _ := strconv.Atoi(m[6])
+		spells = append(spells, pc.Spell{
+			Letter:    rune(m[1][0]),
+			Name:      m[2],
+			Level:     level,
+			Forgotten: m[4] != "" || m[7] == "(gone)",
+			School:    item.School(m[5]),
+			Fail:      fail,
+		})
+	}
+	return spells, errs
+}
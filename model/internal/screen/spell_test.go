@@ -0,0 +1,52 @@
+package screen
+
+import (
+	"testing"
+
+	"github.com/jaguilar/nh/model/item"
+	"github.com/jaguilar/nh/model/pc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSpells(t *testing.T) {
+	s := screenOf(
+		" Choose which spell to cast",
+		"",
+		"     Name                 Level  Category     Fail",
+		" a - force bolt             1    attack         0%",
+		" b - sleep                  1*   enchantment   13%",
+		" c - extra healing          3    healing      100%",
+		" ??? something we can't parse",
+		" (end)",
+	)
+	assert.Equal(t, MenuSpell, s.NextMenu(MenuNone))
+
+	spells, errs := s.ParseSpells()
+	assert.Equal(t, []string{"??? something we can't parse"}, errs)
+	assert.Equal(t, []pc.Spell{
+		{Letter: 'a', Name: "force bolt", Level: 1, School: item.Attack, Fail: 0},
+		{Letter: 'b', Name: "sleep", Level: 1, School: item.Enchantment, Forgotten: true, Fail: 13},
+		{Letter: 'c', Name: "extra healing", Level: 3, School: item.Healing, Fail: 100},
+	}, spells)
+}
+
+func TestParseSpells36(t *testing.T) {
+	s := screenOf(
+		" Choose which spell to cast",
+		"",
+		"     Name                 Level Category     Fail Retention",
+		" a - force bolt             1   attack         0%  76%-100%",
+		" b - sleep                  1   enchantment   13%    (gone)",
+		" c - extra healing          3   healing      100%      100%",
+		" (end)",
+	)
+	assert.Equal(t, MenuSpell, s.NextMenu(MenuNone))
+
+	spells, errs := s.ParseSpells()
+	assert.Empty(t, errs)
+	assert.Equal(t, []pc.Spell{
+		{Letter: 'a', Name: "force bolt", Level: 1, School: item.Attack, Fail: 0},
+		{Letter: 'b', Name: "sleep", Level: 1, School: item.Enchantment, Forgotten: true, Fail: 13},
+		{Letter: 'c', Name: "extra healing", Level: 3, School: item.Healing, Fail: 100},
+	}, spells)
+}
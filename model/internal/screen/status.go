@@ -0,0 +1,209 @@
+package screen
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/jaguilar/nh/model/pc"
+)
+
+// The status lines are always drawn just below the map, whatever the height
+// of the terminal.
+const (
+	statusLine1 = 22
+	statusLine2 = 23
+)
+
+var (
+	status1Re = regexp.MustCompile(`^(?P<name>.+?)\s+` +
+		`St:(?P<st>\d+(?:/(?:\d\d|\*\*))?)\s+Dx:(?P<dx>\d+)\s+Co:(?P<co>\d+)\s+` +
+		`In:(?P<in>\d+)\s+Wi:(?P<wi>\d+)\s+Ch:(?P<ch>\d+)\s+` +
+		`(?P<align>Lawful|Neutral|Chaotic)(?:\s+S:\d+)?$`)
+
+	status2Re = regexp.MustCompile(`^(?:Dlvl:(?P<dlvl>\d+)|Home (?P<home>\d+)|(?P<end>End Game))\s+` +
+		`\S:(?P<gold>\d+)\s+HP:(?P<hp>-?\d+)\((?P<hpmax>\d+)\)\s+Pw:(?P<pw>\d+)\((?P<pwmax>\d+)\)\s+` +
+		`AC:(?P<ac>-?\d+)\s+(?:(?:Xp|Exp):(?P<xl>\d+)(?:/(?P<exp>\d+))?|HD:(?P<hd>\d+))` +
+		`(?:\s+T:(?P<turn>\d+))?(?P<rest>(?:\s+\w+)*)$`)
+)
+
+var (
+	hungerWords = map[string]pc.Hunger{
+		"Satiated": pc.Satiated,
+		"Hungry":   pc.Hungry,
+		"Weak":     pc.Weak,
+		"Fainting": pc.Fainting,
+		"Fainted":  pc.Fainted,
+	}
+	encumbranceWords = map[string]pc.Encumbrance{
+		"Burdened":   pc.Burdened,
+		"Stressed":   pc.Stressed,
+		"Strained":   pc.Strained,
+		"Overtaxed":  pc.Overtaxed,
+		"Overloaded": pc.Overloaded,
+	}
+	conditionWords = map[string]pc.Condition{
+		"Conf":     pc.Conf,
+		"Stun":     pc.Stun,
+		"Blind":    pc.Blind,
+		"Hallu":    pc.Hallu,
+		"Ill":      pc.Ill,
+		"TermIll":  pc.Ill,
+		"FoodPois": pc.FoodPois,
+		"Slime":    pc.Slime,
+		"Stone":    pc.Stone,
+		"Strngl":   pc.Strangled,
+		"Deaf":     pc.Deaf,
+		"Lev":      pc.Lev,
+		"Fly":      pc.Fly,
+		"Ride":     pc.Ride,
+	}
+)
+
+// ParseStatus parses the two status lines at the bottom of the screen into p.
+// Both the default format and the one with the showexp and time options are
+// understood. If either line can't be parsed, p is left unchanged and an error
+// is returned. This happens routinely when a menu is covering the status
+// lines.
+func (s Screen) ParseStatus(p *pc.Player) error {
+	if len(s) <= statusLine2 {
+		return fmt.Errorf("screen too short for status lines: %d rows", len(s))
+	}
+	l1 := strings.TrimSpace(string(s[statusLine1]))
+	l2 := strings.TrimSpace(string(s[statusLine2]))
+	m1 := matchMap(status1Re, status1Re.FindStringSubmatch(l1))
+	if m1 == nil {
+		return fmt.Errorf("can't parse status line 1: %q", l1)
+	}
+	m2 := matchMap(status2Re, status2Re.FindStringSubmatch(l2))
+	if m2 == nil {
+		return fmt.Errorf("can't parse status line 2: %q", l2)
+	}
+
+	// Work on a copy so that p is only updated if everything parses.
+	u := *p
+
+	str, err := parseStr(m1["st"])
+	if err != nil {
+		return err
+	}
+	u.Str = str
+	u.Dex = atoi(m1["dx"])
+	u.Con = atoi(m1["co"])
+	u.Int = atoi(m1["in"])
+	u.Wis = atoi(m1["wi"])
+	u.Cha = atoi(m1["ch"])
+	switch m1["align"] {
+	case "Lawful":
+		u.Alignment = pc.Lawful
+	case "Neutral":
+		u.Alignment = pc.Neutral
+	case "Chaotic":
+		u.Alignment = pc.Chaotic
+	}
+	if i := strings.Index(m1["name"], " the "); i >= 0 {
+		u.Name, u.Title = m1["name"][:i], m1["name"][i+len(" the "):]
+	} else {
+		u.Name, u.Title = m1["name"], ""
+	}
+	if r, ok := pc.RoleOf(u.Title); ok {
+		u.Role = r
+	}
+
+	u.Quest, u.Endgame = false, false
+	switch {
+	case m2["dlvl"] != "":
+		u.Dlvl = atoi(m2["dlvl"])
+	case m2["home"] != "":
+		u.Dlvl = atoi(m2["home"])
+		u.Quest = true
+	default:
+		u.Dlvl = 0
+		u.Endgame = true
+	}
+	u.Gold = atoi(m2["gold"])
+	u.Hp, u.HpMax = atoi(m2["hp"]), atoi(m2["hpmax"])
+	u.Pow, u.PowMax = atoi(m2["pw"]), atoi(m2["pwmax"])
+	u.AC = atoi(m2["ac"])
+	if hd, ok := m2["hd"]; ok {
+		u.XL = atoi(hd)
+	} else {
+		u.XL = atoi(m2["xl"])
+	}
+	if exp, ok := m2["exp"]; ok {
+		u.Exp = atoi(exp)
+	}
+	if turn, ok := m2["turn"]; ok {
+		u.Turn = atoi(turn)
+	}
+
+	u.Hunger, u.Encumbrance, u.Conditions = pc.NotHungry, pc.Unencumbered, 0
+	for _, w := range strings.Fields(m2["rest"]) {
+		if h, ok := hungerWords[w]; ok {
+			u.Hunger = h
+		} else if e, ok := encumbranceWords[w]; ok {
+			u.Encumbrance = e
+		} else if c, ok := conditionWords[w]; ok {
+			u.Conditions |= c
+		} else {
+			return fmt.Errorf("unknown status %q in: %q", w, l2)
+		}
+	}
+
+	*p = u
+	return nil
+}
+
+// parseStr parses a strength as shown on the status line into the encoding
+// described by pc.Str18.
+func parseStr(s string) (int, error) {
+	parts := strings.SplitN(s, "/", 2)
+	base, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, err
+	}
+	if len(parts) == 1 {
+		if base > 18 {
+			return base + 100, nil
+		}
+		return base, nil
+	}
+	if base != 18 {
+		return 0, fmt.Errorf("only 18 can have a percentile strength: %s", s)
+	}
+	if parts[1] == "**" {
+		return pc.Str18(100), nil
+	}
+	x, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, err
+	}
+	return pc.Str18(x), nil
+}
+
+// atoi is strconv.Atoi for strings that our regexps have already checked
+// are numbers.
+func atoi(s string) int {
+	i, err := strconv.Atoi(s)
+	if err != nil {
+		panic(err)
+	}
+	return i
+}
+
+// matchMap returns a map of each subexpression name to its match, if any.
+// A nil return value indicates no match.
+func matchMap(re *regexp.Regexp, s []string) map[string]string {
+	if s == nil {
+		return nil
+	}
+	m := make(map[string]string)
+	for i, subexpName := range re.SubexpNames() {
+		if subexpName == "" || s[i] == "" {
+			continue
+		}
+		m[subexpName] = s[i]
+	}
+	return m
+}
@@ -0,0 +1,68 @@
+package screen
+
+import (
+	"testing"
+
+	"github.com/jaguilar/nh/model/pc"
+	"github.com/stretchr/testify/assert"
+)
+
+// statusScreen makes a screen with the given status lines.
+func statusScreen(l1, l2 string) Screen {
+	lines := make([]string, 24)
+	lines[statusLine1], lines[statusLine2] = l1, l2
+	return screenOf(lines...)
+}
+
+func TestParseStatus(t *testing.T) {
+	var p pc.Player
+	s := statusScreen(
+		"Agent the Stripling          St:18/02 Dx:14 Co:18 In:8 Wi:9 Ch:7  Neutral S:0",
+		"Dlvl:3 $:12 HP:16(18) Pw:2(5) AC:6 Xp:2/25 T:1234 Hungry Burdened Conf Blind")
+	if !assert.Nil(t, s.ParseStatus(&p)) {
+		return
+	}
+	assert.Equal(t, pc.Player{
+		Name: "Agent", Title: "Stripling", Role: pc.Valkyrie,
+		Str: 20, Dex: 14, Con: 18, Int: 8, Wis: 9, Cha: 7,
+		Alignment: pc.Neutral,
+		Dlvl:      3, Gold: 12, Hp: 16, HpMax: 18, Pow: 2, PowMax: 5, AC: 6,
+		XL: 2, Exp: 25, Turn: 1234,
+		Hunger: pc.Hungry, Encumbrance: pc.Burdened,
+		Conditions: pc.Conf | pc.Blind,
+	}, p)
+
+	// Default options: no experience points or turn counter. Conditions
+	// that are gone must be cleared.
+	s = statusScreen(
+		"Agent the Stripling  St:18/** Dx:14 Co:18 In:8 Wi:9 Ch:7 Lawful",
+		"Dlvl:4 $:0 HP:-1(18) Pw:2(5) AC:-3 Exp:5")
+	if assert.Nil(t, s.ParseStatus(&p)) {
+		assert.Equal(t, 118, p.Str)
+		assert.Equal(t, pc.Lawful, p.Alignment)
+		assert.Equal(t, -1, p.Hp)
+		assert.Equal(t, -3, p.AC)
+		assert.Equal(t, 5, p.XL)
+		assert.Equal(t, pc.NotHungry, p.Hunger)
+		assert.Equal(t, pc.Condition(0), p.Conditions)
+	}
+
+	s = statusScreen(
+		"Agent the Newt  St:19 Dx:14 Co:18 In:8 Wi:9 Ch:7 Chaotic",
+		"Home 2 $:0 HP:3(3) Pw:2(5) AC:8 HD:0 T:5000 Stone Strngl")
+	if assert.Nil(t, s.ParseStatus(&p)) {
+		assert.Equal(t, "Newt", p.Title)
+		assert.Equal(t, pc.Valkyrie, p.Role, "kept while polymorphed")
+		assert.Equal(t, 119, p.Str)
+		assert.Equal(t, 2, p.Dlvl)
+		assert.True(t, p.Quest)
+		assert.True(t, p.Conditions.Has(pc.Stone|pc.Strangled))
+	}
+}
+
+func TestParseStatusFailure(t *testing.T) {
+	p := pc.Player{Hp: 7}
+	s := statusScreen("(end)", "")
+	assert.NotNil(t, s.ParseStatus(&p))
+	assert.Equal(t, pc.Player{Hp: 7}, p)
+}
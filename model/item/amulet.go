@@ -3,8 +3,6 @@ package item
 import "io"
 
 func init() {
-	// TODO(jaguilar): fill in the possible appearances, load those into the
-	// appearance table.
 	var data = `name,price,weight,probabilty,eat,appearance
 amulet of change,150,20,130c,Y,
 amulet of ESP,150,20,175,Y,
@@ -21,14 +19,19 @@ Amulet of Yendor,30000,20,0,,`
 
 	for csv.next() {
 		c := &Class{
-			Category:   Amulet,
-			Name:       csv.get("name"),
-			Price:      mustInt(csv.get("price")),
-			Weight:     mustInt(csv.get("weight")),
-			Edible:     "" != csv.get("eat"),
-			Appearance: csv.get("appearance"),
+			Category:    Amulet,
+			Name:        csv.get("name"),
+			Price:       mustInt(csv.get("price")),
+			Weight:      mustInt(csv.get("weight")),
+			Probability: mustProb(csv.get("probabilty")),
+			Edible:      "" != csv.get("eat"),
+			Appearance:  csv.get("appearance"),
+			Unique:      csv.get("name") == "Amulet of Yendor",
 		}
 		classes[c.Name] = c
+		if c.Appearance == "" && c.Name != "Amulet of Yendor" {
+			amuletShuffle.names = append(amuletShuffle.names, c.Name)
+		}
 	}
 	if csv.err != io.EOF {
 		panic(csv.err)
@@ -0,0 +1,136 @@
+package item
+
+import "github.com/jaguilar/nh/model/anatomy"
+
+// A shuffle is a group of item classes whose appearances are shuffled at the
+// start of each game. Within a game, each class in the group gets a different
+// one of the appearances. There may be more appearances than classes, in
+// which case some appearances are never seen.
+//
+// Gems are the odd one out. Their appearances aren't shuffled, but several
+// classes share each one: a "white gem" may be a diamond or worthless glass.
+// For them, shared lists the classes that share each appearance.
+type shuffle struct {
+	Category
+
+	// appearances are the appearances as nethack shows them for an item
+	// that hasn't been identified: "wooden ring", "scroll labeled NR 9".
+	appearances []string
+
+	// names are the names of the classes in the group. They are filled in as
+	// each category's data is loaded.
+	names []string
+
+	// shared is set for groups like the gems, and maps each appearance to
+	// the names of the classes that have it.
+	shared map[string][]string
+}
+
+// bijective returns whether each appearance in the group belongs to exactly
+// one class, so that every appearance is in use in every game.
+func (s *shuffle) bijective() bool {
+	return s.shared == nil && len(s.appearances) == len(s.names)
+}
+
+// suffixed returns each appearance with the category's name appended.
+func suffixed(suffix string, appearances ...string) []string {
+	out := make([]string, len(appearances))
+	for i, a := range appearances {
+		out[i] = a + suffix
+	}
+	return out
+}
+
+// prefixed returns each appearance with the category's name prepended.
+func prefixed(prefix string, appearances ...string) []string {
+	out := make([]string, len(appearances))
+	for i, a := range appearances {
+		out[i] = prefix + a
+	}
+	return out
+}
+
+var (
+	amuletShuffle = &shuffle{Category: Amulet, appearances: suffixed(" amulet",
+		"circular", "spherical", "oval", "triangular", "pyramidal", "square",
+		"concave", "hexagonal", "octagonal")}
+
+	ringShuffle = &shuffle{Category: Ring, appearances: suffixed(" ring",
+		"wooden", "granite", "opal", "clay", "coral", "black onyx", "moonstone",
+		"tiger eye", "jade", "bronze", "agate", "topaz", "sapphire", "ruby",
+		"diamond", "ivory", "emerald", "silver", "iron", "brass", "steel",
+		"twisted", "pearl", "wire", "engagement", "shiny", "gold", "copper")}
+
+	wandShuffle = &shuffle{Category: Wand, appearances: suffixed(" wand",
+		"glass", "balsa", "crystal", "maple", "pine", "oak", "ebony", "marble",
+		"tin", "brass", "copper", "silver", "platinum", "iridium", "zinc",
+		"aluminum", "uranium", "iron", "steel", "hexagonal", "short", "runed",
+		"long", "curved", "forked", "spiked", "jeweled")}
+
+	potionShuffle = &shuffle{Category: Potion, appearances: suffixed(" potion",
+		"ruby", "pink", "orange", "yellow", "emerald", "dark green", "cyan",
+		"sky blue", "brilliant blue", "magenta", "purple-red", "puce", "milky",
+		"swirly", "bubbly", "smoky", "cloudy", "effervescent", "black",
+		"golden", "brown", "fizzy", "dark", "white", "murky")}
+
+	scrollShuffle = &shuffle{Category: Scroll, appearances: prefixed("scroll labeled ",
+		"ZELGO MER", "JUYED AWK YACC", "NR 9", "XIXAXA XOXAXA XUXAXA",
+		"PRATYAVAYAH", "DAIYEN FOOELS", "LEP GEX VEN ZEA", "PRIRUTSENIE",
+		"ELBIB YLOH", "VERR YED HORRE", "VENZAR BORGAVVE", "THARR", "YUM YUM",
+		"KERNOD WEL", "ELAM EBOW", "DUAM XNAHT", "ANDOVA BEGARIN", "KIRJE",
+		"VE FORBRYDERNE", "HACKEM MUCHE", "VELOX NEB", "FOOBIE BLETCH",
+		"TEMOV", "GARVEN DEH", "READ ME", "ETAOIN SHRDLU", "LOREM IPSUM",
+		"FNORD", "KO BATE", "ABRA KA DABRA", "ASHPD SODALG", "ZLORFIK",
+		"GNIK SISI VLE", "HAPAX LEGOMENON", "EIRIS SAZUN IDISI",
+		"PHOL ENDE WODAN", "GHOTI", "MAPIRO MAHAMA DIROMAT",
+		"VAS CORP BET MANI", "XOR OTA", "STRC PRST SKRZ KRK")}
+
+	spellbookShuffle = &shuffle{Category: Spellbook, appearances: suffixed(" spellbook",
+		"parchment", "vellum", "ragged", "dog eared", "mottled", "stained",
+		"cloth", "leathery", "white", "pink", "red", "orange", "yellow",
+		"velvet", "light green", "dark green", "turquoise", "cyan",
+		"light blue", "dark blue", "indigo", "magenta", "purple", "violet",
+		"tan", "plaid", "light brown", "dark brown", "gray", "wrinkled",
+		"dusty", "bronze", "copper", "silver", "gold", "glittering",
+		"shining", "dull", "thin", "thick")}
+
+	// armorShuffles are the groups of armor with shuffled appearances, by the
+	// slot they're worn in. Their appearances come from the armor table.
+	armorShuffles = map[anatomy.BodyPart]*shuffle{
+		anatomy.TorsoOver: {Category: Armor},
+		anatomy.Head:      {Category: Armor},
+		anatomy.Arms:      {Category: Armor},
+		anatomy.Feet:      {Category: Armor},
+	}
+
+	// gemShuffle is the group of gems and stones that share appearances. It
+	// is filled in by the gem table.
+	gemShuffle = &shuffle{Category: Gem, shared: make(map[string][]string)}
+
+	// shuffles are all the groups of classes with shuffled appearances.
+	shuffles = []*shuffle{
+		amuletShuffle, ringShuffle, wandShuffle, potionShuffle, scrollShuffle, spellbookShuffle,
+		armorShuffles[anatomy.TorsoOver], armorShuffles[anatomy.Head],
+		armorShuffles[anatomy.Arms], armorShuffles[anatomy.Feet],
+		gemShuffle,
+	}
+)
+
+// genericNames are the words nethack uses for an item whose appearance it
+// isn't showing, as in "potion called healing" or "a scroll" when blind.
+var genericNames = map[string]Class{
+	"amulet":    {Category: Amulet},
+	"ring":      {Category: Ring},
+	"wand":      {Category: Wand},
+	"potion":    {Category: Potion},
+	"scroll":    {Category: Scroll},
+	"spellbook": {Category: Spellbook},
+	"gem":       {Category: Gem},
+	"stone":     {Category: Gem, Material: Mineral},
+	"armor":     {Category: Armor},
+	"cloak":     {Category: Armor, Slots: []anatomy.BodyPart{anatomy.TorsoOver}},
+	"helmet":    {Category: Armor, Slots: []anatomy.BodyPart{anatomy.Head}},
+	"gloves":    {Category: Armor, Slots: []anatomy.BodyPart{anatomy.Arms}},
+	"boots":     {Category: Armor, Slots: []anatomy.BodyPart{anatomy.Feet}},
+	"shield":    {Category: Armor, Slots: []anatomy.BodyPart{anatomy.Hand}},
+}
@@ -7,7 +7,8 @@ import (
 )
 
 func init() {
-	// Currently don't parse eff or appearances preceded by *.
+	// Currently don't parse eff. Appearances preceded by * are shuffled
+	// among the armor worn in the same slot.
 	armorData := `name,price,weight,probability,ac,material,effect,mc,appearance,slot
 Hawaiian shirt,3,5,8,0,cloth,Shops,,,torso under
 T-shirt,2,5,2,0,cloth,Shops,,,torso under
@@ -37,7 +38,7 @@ silver dragon scales,700,40,0,3,dragon,Reflect,,,torso
 gray dragon scales,700,40,0,3,dragon,Magic,,,torso
 red dragon scale mail,900,40,0,9,dragon,Fire,,,torso
 white dragon scale mail,900,40,0,9,dragon,Cold,,,torso
-orange dragon scale mail:,900,40,0,9,dragon,Sleep,,,torso
+orange dragon scale mail,900,40,0,9,dragon,Sleep,,,torso
 blue dragon scale mail,900,40,0,9,dragon,Elec,,,torso
 green dragon scale mail,900,40,0,9,dragon,Poison,,,torso
 yellow dragon scale mail,900,40,0,9,dragon,Acd,,,torso
@@ -52,7 +53,7 @@ cloak of displacement,50,10,10,1,cloth,##Displ,2,*piece of cloth,torso over
 oilskin cloak,50,10,10,1,cloth,###Water,3,slippery cloak,torso over
 alchemy smock,50,10,9,1,cloth,#Poi+Acd,1,apron,torso over
 cloak of invisibility,60,10,10,1,cloth,##Invis,2,*opera cloak,torso over
-clk of magic resistance,60,10,2,1,cloth,###Magic,3,*ornamental cope,torso over
+cloak of magic resistance,60,10,2,1,cloth,###Magic,3,*ornamental cope,torso over
 elven cloak,60,10,8,1,cloth,###Stlth,3,faded pall,torso over
 robe,50,15,3,2,cloth,###Spell,3,,torso over
 cloak of protection,50,10,9,3,cloth,###Prot,3,*tattered cape,torso over
@@ -92,11 +93,14 @@ iron shoes,16,50,7,2,iron,,,hard shoes,feet`
 
 	for csv.next() {
 		name, alt := parseAltName(csv.get("name"))
+		slot := anatomy.BodyPart(csv.get("slot"))
 		appearance := csv.get("appearance")
 		if appearance != "" && appearance[0] == '*' {
-			// * in appearance in this table indicates that it is one of several
-			// alternates. We will eventually load these into a list of alternates,
-			// but for now we will simply ignore them.
+			// The appearance in the table is just one of the group's. Which
+			// one this class has varies from game to game.
+			s := armorShuffles[slot]
+			s.appearances = append(s.appearances, appearance[1:])
+			s.names = append(s.names, name)
 			appearance = ""
 		}
 
@@ -105,13 +109,17 @@ iron shoes,16,50,7,2,iron,,,hard shoes,feet`
 			Name:              name,
 			Price:             mustInt(csv.get("price")),
 			Weight:            mustInt(csv.get("weight")),
+			Probability:       mustProb(csv.get("probability")),
 			AC:                mustInt(csv.get("ac")),
 			Material:          Material(csv.get("material")),
 			MagicCancellation: mustInt(csv.get("mc")),
-			Slots:             []anatomy.BodyPart{anatomy.BodyPart(csv.get("slot"))},
+			Appearance:        appearance,
+			Slots:             []anatomy.BodyPart{slot},
 		}
 		classes[c.Name] = c
-		classes[alt] = c
+		if alt != "" {
+			classes[alt] = c
+		}
 	}
 	if csv.err != io.EOF {
 		panic(csv.err)
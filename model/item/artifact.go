@@ -1,9 +1,281 @@
 package item
 
-type artifactKey struct {
-	name, class string
+import (
+	"io"
+	"strings"
+)
+
+// Artifact is one of nethack's artifacts: a one-of-a-kind item of a base
+// class, with powers of its own. An artifact item's Class is its base
+// class, so its damage, AC and so on are the base class's. Its Named is the
+// artifact's name.
+type Artifact struct {
+	// Name is the artifact's name, without the "The" some have: "Master
+	// Key of Thievery".
+	Name string
+
+	// Base is the name of the artifact's base class: "runesword".
+	Base string
+
+	// Align is "Lawful", "Neutral" or "Chaotic", as on the status line, or
+	// "" if the artifact is unaligned.
+	Align string
+
+	// Role is the role the artifact belongs to, as pc.Role names it, or ""
+	// if it belongs to none. Race is the race it belongs to, if any: Sting
+	// and Orcrist are elven, and Grimtooth is orcish.
+	Role, Race string
+
+	// Intelligent artifacts refuse to be handled by players of the wrong
+	// alignment, and blast them.
+	Intelligent bool
+
+	// Quest is set for the quest artifacts, which can't be wished for.
+	Quest bool
+
+	// NoGen is set for the artifacts that nethack never makes at random or
+	// gives as a sacrifice gift: Excalibur and the quest artifacts.
+	NoGen bool
+
+	// Price is the artifact's base price in shops.
+	Price int
+
+	// Attack is the artifact's bonus to hit and damage.
+	Attack ArtifactAttack
+
+	// Defense is the damage the artifact protects its wielder or wearer
+	// from. Carried is the damage it protects whoever carries it from.
+	Defense, Carried AttackType
+
+	// Props are the properties the artifact gives when it's wielded or
+	// worn, and CarriedProps the ones it gives when it's carried.
+	Props, CarriedProps []Property
+
+	// Invoke is what happens when the artifact is #invoked, or "" if
+	// nothing does.
+	Invoke string
+}
+
+// ArtifactAttack is an artifact's bonus to hit and damage, against the
+// monsters it's effective against.
+type ArtifactAttack struct {
+	// Type is the type of damage. An artifact with no attack has no Type.
+	Type AttackType
+
+	// ToHit is the size of the die rolled for the bonus to hit.
+	ToHit int
+
+	// Damage is the size of the die rolled for the bonus damage. If it's 0,
+	// the weapon's damage is doubled instead.
+	Damage int
+
+	// Against is the kind of monster the attack is effective against:
+	// "orcs", "demons", "cross-aligned" and so on. It's "" if the attack is
+	// effective against everything.
+	Against string
+}
+
+// AttackType is a type of damage that an artifact deals or protects from.
+type AttackType string
+
+// The attack types artifacts have.
+const (
+	NoAttack     AttackType = ""
+	Physical     AttackType = "physical"
+	Fire         AttackType = "fire"
+	Cold         AttackType = "cold"
+	Shock        AttackType = "shock"
+	DrainLife    AttackType = "drain life"
+	Stun         AttackType = "stun"
+	MagicMissile AttackType = "magic missile"
+	Blinding     AttackType = "blinding"
+	Lycanthropy  AttackType = "lycanthropy"
+)
+
+// Property is an extrinsic that an item gives.
+type Property string
+
+// The properties artifacts give.
+const (
+	Searching           Property = "searching"
+	Warning             Property = "warning"
+	ESP                 Property = "ESP"
+	Stealth             Property = "stealth"
+	Regeneration        Property = "regeneration"
+	EnergyRegeneration  Property = "energy regeneration"
+	HalfSpellDamage     Property = "half spell damage"
+	HalfPhysicalDamage  Property = "half physical damage"
+	TeleportControl     Property = "teleport control"
+	Luck                Property = "luck"
+	XRay                Property = "x-ray vision"
+	Reflection          Property = "reflection"
+	HallucinationRes    Property = "hallucination resistance"
+	Protection          Property = "protection"
+	Beheading           Property = "beheading"
+	Speaking            Property = "speaking"
+	DrainLifeResistance Property = "drain resistance"
+)
+
+// artifactProps are the names the artifact table uses for properties.
+var artifactProps = map[string]Property{
+	"search": Searching, "warn": Warning, "esp": ESP, "stealth": Stealth,
+	"regen": Regeneration, "eregen": EnergyRegeneration, "hspdam": HalfSpellDamage,
+	"hphdam": HalfPhysicalDamage, "tctrl": TeleportControl, "luck": Luck,
+	"xray": XRay, "reflect": Reflection, "halres": HallucinationRes,
+	"protect": Protection, "behead": Beheading, "speak": Speaking,
+	"drli": DrainLifeResistance,
+}
+
+var (
+	// artifacts are the artifacts, by name.
+	artifacts = make(map[string]*Artifact)
+
+	// artifactList is the artifacts in nethack's order.
+	artifactList []*Artifact
+)
+
+func init() {
+	// Attacks are "type hit dam", as in nethack's artilist.h: "physical 5
+	// 0" is +d5 to hit and double damage.
+	data := `name,base,align,role,race,attack,against,defense,carried,props,carried props,invoke,price,flags
+Excalibur,long sword,Lawful,Knight,,physical 5 10,,drain life,,search,,,4000,intelligent|nogen
+Stormbringer,runesword,Chaotic,,,drain life 5 2,,drain life,,drli,,,8000,intelligent
+Mjollnir,war hammer,Neutral,Valkyrie,,shock 5 24,,,,,,,4000,
+Cleaver,battle-axe,Neutral,Barbarian,,physical 3 6,,,,,,,1500,
+Grimtooth,orcish dagger,Chaotic,,orc,physical 2 6,,,,,,,300,
+Orcrist,elven broadsword,Chaotic,,elf,physical 5 0,orcs,,,warn,,,2000,
+Sting,elven dagger,Chaotic,,elf,physical 5 0,orcs,,,warn,,,800,
+Magicbane,athame,Neutral,Wizard,,stun 3 4,,magic missile,,,,,3500,
+Frost Brand,long sword,,,,cold 5 0,,cold,,,,,3000,
+Fire Brand,long sword,,,,fire 5 0,,fire,,,,,3000,
+Dragonbane,broadsword,,,,physical 5 0,dragons,,,,,,500,
+Demonbane,long sword,Lawful,,,physical 5 0,demons,,,,,,2500,
+Werebane,silver saber,,,,physical 5 0,lycanthropes,lycanthropy,,,,,1500,
+Grayswandir,silver saber,Lawful,,,physical 5 0,,,,halres,,,8000,
+Giantslayer,long sword,Neutral,,,physical 5 0,giants,,,,,,200,
+Ogresmasher,war hammer,,,,physical 5 0,ogres,,,,,,200,
+Trollsbane,morning star,,,,physical 5 0,trolls,,,,,,200,
+Vorpal Blade,long sword,Neutral,,,physical 5 1,,,,behead,,,4000,
+Snickersnee,katana,Lawful,Samurai,,physical 0 8,,,,,,,1200,
+Sunsword,long sword,Lawful,,,physical 5 0,undead,blinding,,,,,1500,
+Orb of Detection,crystal ball,Lawful,Archeologist,,,,,magic missile,,esp|hspdam,invisibility,2500,intelligent|quest|nogen
+Heart of Ahriman,luckstone,Neutral,Barbarian,,physical 5 0,,,,,stealth,levitation,2500,intelligent|quest|nogen
+Sceptre of Might,mace,Lawful,Caveman,,physical 5 0,cross-aligned,magic missile,,,,conflict,2500,intelligent|quest|nogen
+Staff of Aesculapius,quarterstaff,Neutral,Healer,,drain life 0 0,,drain life,,drli|regen,,healing,5000,intelligent|quest|nogen
+Magic Mirror of Merlin,mirror,Lawful,Knight,,,,,magic missile,speak,esp,,1500,intelligent|quest|nogen
+Eyes of the Overworld,lenses,Neutral,Monk,,,,magic missile,,xray,,enlightenment,2500,intelligent|quest|nogen
+Mitre of Holiness,helm of brilliance,Lawful,Priest,,,undead,,fire,,,energy boost,2000,intelligent|quest|nogen
+Longbow of Diana,bow,Chaotic,Ranger,,physical 5 0,,,,reflect,esp,create ammo,4000,intelligent|quest|nogen
+Master Key of Thievery,skeleton key,Chaotic,Rogue,,,,,,speak,warn|tctrl|hphdam,untrap,3500,intelligent|quest|nogen
+Tsurugi of Muramasa,tsurugi,Lawful,Samurai,,physical 0 8,,,,behead|luck|protect,,,4500,intelligent|quest|nogen
+Platinum Yendorian Express Card,credit card,Neutral,Tourist,,,,,magic missile,,esp|hspdam,charge,7000,intelligent|quest|nogen
+Orb of Fate,crystal ball,Neutral,Valkyrie,,,,,,luck,warn|hspdam|hphdam,level teleport,3500,intelligent|quest|nogen
+Eye of the Aethiopica,amulet of ESP,Neutral,Wizard,,,,magic missile,,,eregen|hspdam,create portal,4000,intelligent|quest|nogen`
+	csv := mustMapCsv(data)
+
+	for csv.next() {
+		a := &Artifact{
+			Name:         csv.get("name"),
+			Base:         csv.get("base"),
+			Align:        csv.get("align"),
+			Role:         csv.get("role"),
+			Race:         csv.get("race"),
+			Price:        mustInt(csv.get("price")),
+			Defense:      AttackType(csv.get("defense")),
+			Carried:      AttackType(csv.get("carried")),
+			Props:        parseProps(csv.get("props")),
+			CarriedProps: parseProps(csv.get("carried props")),
+			Invoke:       csv.get("invoke"),
+		}
+		if at := csv.get("attack"); at != "" {
+			// The type may be more than one word.
+			f := strings.Fields(at)
+			n := len(f)
+			a.Attack = ArtifactAttack{
+				Type:    AttackType(strings.Join(f[:n-2], " ")),
+				ToHit:   mustInt(f[n-2]),
+				Damage:  mustInt(f[n-1]),
+				Against: csv.get("against"),
+			}
+		} else {
+			a.Attack.Against = csv.get("against")
+		}
+		for _, f := range strings.Split(csv.get("flags"), "|") {
+			switch f {
+			case "intelligent":
+				a.Intelligent = true
+			case "quest":
+				a.Quest = true
+			case "nogen":
+				a.NoGen = true
+			case "":
+			default:
+				panic("unknown artifact flag: " + f)
+			}
+		}
+		artifacts[a.Name] = a
+		artifactList = append(artifactList, a)
+	}
+	if csv.err != io.EOF {
+		panic(csv.err)
+	}
+}
+
+// parseProps parses a list of properties from the artifact table.
+func parseProps(s string) []Property {
+	var ps []Property
+	for _, p := range strings.Split(s, "|") {
+		if p == "" {
+			continue
+		}
+		prop, ok := artifactProps[p]
+		if !ok {
+			panic("unknown artifact property: " + p)
+		}
+		ps = append(ps, prop)
+	}
+	return ps
+}
+
+// ArtifactNamed returns the artifact called name, or nil if there's none.
+// The "The" that some artifacts' names begin with is optional.
+func ArtifactNamed(name string) *Artifact {
+	return artifacts[strings.TrimPrefix(name, "The ")]
+}
+
+// ArtifactsOf returns the artifacts whose base class is called base, in
+// nethack's order.
+func ArtifactsOf(base string) []*Artifact {
+	var as []*Artifact
+	for _, a := range artifactList {
+		if a.Base == base {
+			as = append(as, a)
+		}
+	}
+	return as
+}
+
+// BaseClass returns the artifact's base class.
+func (a *Artifact) BaseClass() *Class {
+	return classes[a.Base]
+}
+
+// Wishable returns whether the artifact can be wished for. Quest artifacts
+// can't be. (Any artifact may still fail to appear if others exist.)
+func (a *Artifact) Wishable() bool {
+	return !a.Quest
 }
 
-var artifacts = map[artifactKey]bool{
-	{"Grayswandir", "silver saber"}: true,
+// Giftable returns whether a god might give the artifact to a player of
+// alignment align, who has had gifts gifts before, for a sacrifice.
+// Unaligned artifacts are only given after the first gift. If an artifact
+// of the player's role is giftable, that's the one that's given.
+//
+// Nethack also won't give an artifact of a race hostile to the player's.
+// We leave that to the caller.
+func (a *Artifact) Giftable(align string, gifts int) bool {
+	if a.NoGen {
+		return false
+	}
+	return a.Align == align || a.Align == "" && gifts > 0
 }
@@ -0,0 +1,89 @@
+package item
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArtifactTable(t *testing.T) {
+	assert.Len(t, artifactList, 33)
+	quest := 0
+	for _, a := range artifactList {
+		assert.NotNil(t, a.BaseClass(), a.Name)
+		if a.Quest {
+			quest++
+			assert.True(t, a.Intelligent, a.Name)
+			assert.NotEqual(t, "", a.Role, a.Name)
+		}
+	}
+	assert.Equal(t, 13, quest)
+
+	sting := ArtifactNamed("Sting")
+	if assert.NotNil(t, sting) {
+		assert.Equal(t, ArtifactAttack{Type: Physical, ToHit: 5, Against: "orcs"}, sting.Attack)
+		assert.Equal(t, "elf", sting.Race)
+		assert.Equal(t, []Property{Warning}, sting.Props)
+	}
+	key := ArtifactNamed("The Master Key of Thievery")
+	if assert.NotNil(t, key) {
+		assert.Equal(t, "Rogue", key.Role)
+		assert.Equal(t, []Property{Warning, TeleportControl, HalfPhysicalDamage}, key.CarriedProps)
+		assert.Equal(t, "untrap", key.Invoke)
+		assert.Equal(t, []Property{Speaking}, key.Props)
+	}
+	mirror := ArtifactNamed("The Magic Mirror of Merlin")
+	if assert.NotNil(t, mirror) {
+		assert.Equal(t, []Property{Speaking}, mirror.Props)
+		assert.Equal(t, []Property{ESP}, mirror.CarriedProps)
+	}
+	bow := ArtifactNamed("The Longbow of Diana")
+	if assert.NotNil(t, bow) {
+		assert.Equal(t, []Property{Reflection}, bow.Props)
+		assert.Equal(t, []Property{ESP}, bow.CarriedProps)
+	}
+	assert.Equal(t, DrainLife, ArtifactNamed("Stormbringer").Attack.Type)
+	assert.Nil(t, ArtifactNamed("Mournblade"))
+}
+
+func TestArtifactsOf(t *testing.T) {
+	var names []string
+	for _, a := range ArtifactsOf("long sword") {
+		names = append(names, a.Name)
+	}
+	assert.Equal(t, []string{"Excalibur", "Frost Brand", "Fire Brand", "Demonbane",
+		"Giantslayer", "Vorpal Blade", "Sunsword"}, names)
+	assert.Empty(t, ArtifactsOf("dagger"))
+}
+
+func TestArtifactGifts(t *testing.T) {
+	assert.True(t, ArtifactNamed("Mjollnir").Giftable("Neutral", 0))
+	assert.False(t, ArtifactNamed("Mjollnir").Giftable("Lawful", 0))
+	assert.False(t, ArtifactNamed("Fire Brand").Giftable("Lawful", 0))
+	assert.True(t, ArtifactNamed("Fire Brand").Giftable("Lawful", 1))
+	assert.False(t, ArtifactNamed("Excalibur").Giftable("Lawful", 0))
+	assert.True(t, ArtifactNamed("Excalibur").Wishable())
+	assert.False(t, ArtifactNamed("Orb of Fate").Wishable())
+}
+
+func TestArtifactItems(t *testing.T) {
+	i := mustParse("a - the blessed rustproof +7 Stormbringer (weapon in hand)")
+	if assert.NotNil(t, i.Artifact()) {
+		assert.Equal(t, "Stormbringer", i.Artifact().Name)
+	}
+	// Artifacts are their base class for everything else.
+	assert.Equal(t, classes["runesword"].SmallDam, i.Class.SmallDam)
+
+	i = mustParse("b - the uncursed Eyes of the Overworld (being worn)")
+	assert.Equal(t, "lenses", i.Class.Name)
+	assert.Equal(t, "b - the uncursed Eyes of the Overworld (being worn)", i.String())
+
+	// An artifact we haven't identified is shown by its base's appearance.
+	r := NewRegistry()
+	i, err := r.Parse("c - a runed broadsword named Stormbringer")
+	if assert.Nil(t, err) {
+		assert.Nil(t, i.Artifact())
+		assert.Equal(t, "c - a runed broadsword named Stormbringer", i.String())
+	}
+	assert.Nil(t, mustParse("d - a dagger named Sting").Artifact())
+}
@@ -11,7 +11,7 @@ import (
 const (
 	_Category_name_0 = "PotionAmulet"
 	_Category_name_1 = "CoinsComestible"
-	_Category_name_2 = "ToolWeaponGemSpellbook"
+	_Category_name_2 = "StatueToolWeaponGemSpellbook"
 	_Category_name_3 = "WandHeavyIronBall"
 	_Category_name_4 = "Ring"
 	_Category_name_5 = "Scroll"
@@ -22,7 +22,7 @@ const (
 var (
 	_Category_index_0 = [...]uint8{0, 6, 12}
 	_Category_index_1 = [...]uint8{0, 5, 15}
-	_Category_index_2 = [...]uint8{0, 4, 10, 13, 22}
+	_Category_index_2 = [...]uint8{0, 6, 10, 16, 19, 28}
 	_Category_index_3 = [...]uint8{0, 4, 17}
 	_Category_index_4 = [...]uint8{0, 4}
 	_Category_index_5 = [...]uint8{0, 6}
@@ -38,8 +38,8 @@ func (i Category) String() string {
 	case 36 <= i && i <= 37:
 		i -= 36
 		return _Category_name_1[_Category_index_1[i]:_Category_index_1[i+1]]
-	case 40 <= i && i <= 43:
-		i -= 40
+	case 39 <= i && i <= 43:
+		i -= 39
 		return _Category_name_2[_Category_index_2[i]:_Category_index_2[i+1]]
 	case 47 <= i && i <= 48:
 		i -= 47
@@ -1,14 +1,16 @@
 package item
 
 import (
+	"strings"
+
 	"github.com/jaguilar/nh/model/anatomy"
 	"github.com/jaguilar/nh/model/randfunc"
 )
 
 var (
-	// classes is a list of item classes in the game. Most items like weapons,
-	// armor, tools, etc. are in this list, although some are not. Notably,
-	// corpses and statues of any kind will not be found here.
+	// classes is a list of item classes in the game. Corpses, tins, eggs,
+	// statues and figurines of every species share a class; the species is
+	// recorded on the Item.
 	//
 	// This is private to the package because users should use the Registry to
 	// get information about item classes.
@@ -69,13 +71,139 @@ type Class struct {
 	// MagicCancellation is the degree of magic cancellation conferred by wearing
 	// this item.
 	MagicCancellation int
+
+	// Probability is how many of every 1000 randomly generated items of the
+	// Category are of this class.
+	Probability int
+
+	// Zap is how a wand is aimed.
+	Zap
+
+	// SpellLevel and School are the level and school of the spell a
+	// spellbook teaches.
+	SpellLevel int
+	School
+
+	// Nutrition is how much nutrition a comestible gives when eaten whole.
+	Nutrition int
+
+	// Container is set for tools that hold other items, like sacks and
+	// boxes. Lockable containers can be locked.
+	Container, Lockable bool
+
+	// LightSource is set for tools that can be lit, like lamps and candles.
+	LightSource bool
+
+	// Charged is set for classes whose items have charges, shown as "(0:5)",
+	// like wands and the magic marker.
+	Charged bool
+
+	// Unlocks is set for tools that can open locks. See UnlockChance.
+	Unlocks bool
+
+	// Unique is set for the classes there's only one item of in the game,
+	// like the Amulet of Yendor. Nethack calls them "the".
+	Unique bool
+}
+
+// pair returns whether nethack calls an item of class c a "pair of" them:
+// boots, gloves and lenses.
+func (c *Class) pair() bool {
+	if c.Name == "lenses" {
+		return true
+	}
+	for _, s := range c.Slots {
+		if s == anatomy.Feet || s == anatomy.Arms {
+			return true
+		}
+	}
+	return false
+}
+
+// twoHanded returns whether items of class c take both hands to wield.
+func (c *Class) twoHanded() bool {
+	n := 0
+	for _, s := range c.Slots {
+		if s == anatomy.Hand {
+			n++
+		}
+	}
+	return n == 2
+}
+
+// genericName returns the word nethack uses for an item of class c that
+// it doesn't show the appearance of, as in "potion called oil". Items of
+// other categories are shown by their appearance.
+func (c *Class) genericName() string {
+	switch c.Category {
+	case Armor:
+		for _, s := range c.Slots {
+			switch s {
+			case anatomy.Feet:
+				return "boots"
+			case anatomy.Arms:
+				return "gloves"
+			case anatomy.Head:
+				return "helmet"
+			case anatomy.TorsoOver:
+				return "cloak"
+			case anatomy.Hand:
+				return "shield"
+			}
+		}
+		return "armor"
+	case Gem:
+		if c.Material == Mineral || strings.HasSuffix(c.Appearance, " stone") {
+			return "stone"
+		}
+		return "gem"
+	case Amulet, Ring, Wand, Potion, Scroll, Spellbook:
+		return strings.ToLower(c.Category.String())
+	}
+	return c.Appearance
 }
 
+// Zap is how a wand is aimed when it's zapped.
+type Zap int
+
+const (
+	// NoDir wands aren't aimed. Zapping one affects you or your
+	// surroundings.
+	NoDir Zap = iota
+
+	// Immediate wands affect the first thing in the direction they're
+	// zapped, or everything up to their range, like a wand of striking.
+	Immediate
+
+	// Ray wands shoot a ray that can bounce, like a wand of fire.
+	Ray
+)
+
+var zaps = map[string]Zap{"nodir": NoDir, "immediate": Immediate, "ray": Ray}
+
+// School is the school of magic a spell belongs to. Each role is better at
+// some schools than others.
+type School string
+
+// The schools of magic.
+const (
+	Attack      School = "attack"
+	Healing     School = "healing"
+	Divination  School = "divination"
+	Enchantment School = "enchantment"
+	Clerical    School = "clerical"
+	Escape      School = "escape"
+	Matter      School = "matter"
+)
+
 // Category is the category of an item.
 // +gen stringer
 type Category rune
 
-// The various item categories.
+// The various item categories. Each is the symbol nethack uses for the
+// category, except Statue. Statues are drawn like boulders (or like the
+// monster they depict), so we give them a symbol of their own to tell them
+// apart.
 const (
 	Amulet        Category = '"'
 	Armor         Category = '['
@@ -88,7 +216,7 @@ const (
 	Potion        Category = '!'
 	Scroll        Category = '?'
 	Spellbook     Category = '+'
-	Statue        Category = '`'
+	Statue        Category = '\''
 	Ring          Category = '='
 	Tool          Category = '('
 	Wand          Category = '/'
@@ -101,18 +229,25 @@ type Material string
 
 // Various materials. Might not include all materials in the game.
 const (
-	Iron    Material = "iron"
-	Copper  Material = "copper" // Or bronze.
-	Metal   Material = "metal"  // Generic metal, not iron, silver, copper or mithril.
-	Wood    Material = "wood"
-	Leather Material = "leather"
-	Cloth   Material = "cloth"
-	Plastic Material = "plastic"
-	Glass   Material = "glass"
-	Mithril Material = "mithril"
-	Mineral Material = "mineral"
-	Silver  Material = "silver"
-	Dragon  Material = "dragon"
+	Iron     Material = "iron"
+	Copper   Material = "copper" // Or bronze.
+	Metal    Material = "metal"  // Generic metal, not iron, silver, copper or mithril.
+	Wood     Material = "wood"
+	Leather  Material = "leather"
+	Cloth    Material = "cloth"
+	Plastic  Material = "plastic"
+	Glass    Material = "glass"
+	Mithril  Material = "mithril"
+	Mineral  Material = "mineral"
+	Silver   Material = "silver"
+	Dragon   Material = "dragon"
+	Paper    Material = "paper"
+	Wax      Material = "wax"
+	Bone     Material = "bone"
+	Flesh    Material = "flesh"
+	Veggy    Material = "veggy"
+	Gemstone Material = "gemstone"
+	Gold     Material = "gold"
 )
 
 // FixedString is the string that would describe an object's fixedness
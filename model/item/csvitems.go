@@ -53,3 +53,9 @@ func mustMapCsv(s string) *csvItems {
 	}
 	return r
 }
+
+// mustProb parses a probability column. A trailing "c" marks classes that are
+// usually generated cursed, which we don't record.
+func mustProb(s string) int {
+	return mustInt(strings.TrimSuffix(s, "c"))
+}
@@ -0,0 +1,55 @@
+package item
+
+import "io"
+
+func init() {
+	// The meat ring is in the ring table. A corpse's weight and nutrition
+	// depend on its species, which we don't have the data for.
+	data := `name,price,weight,probability,nutrition,material
+tripe ration,15,10,15,200,flesh
+corpse,0,0,0,0,flesh
+egg,9,1,85,80,flesh
+meatball,5,1,0,5,flesh
+meat stick,5,1,0,5,flesh
+huge chunk of meat,105,400,0,2000,flesh
+kelp frond,6,1,0,30,veggy
+eucalyptus leaf,6,1,3,30,veggy
+apple,7,2,15,50,veggy
+orange,9,2,10,80,veggy
+pear,7,2,10,50,veggy
+melon,10,5,10,100,veggy
+banana,9,2,10,80,veggy
+carrot,7,2,15,50,veggy
+sprig of wolfsbane,7,1,7,40,veggy
+clove of garlic,7,1,7,40,veggy
+slime mold,17,5,75,80,veggy
+lump of royal jelly,15,2,0,200,veggy
+cream pie,10,10,25,100,veggy
+candy bar,10,2,13,100,veggy
+fortune cookie,7,1,55,40,veggy
+pancake,15,2,25,200,veggy
+lembas wafer,45,5,20,800,veggy
+cram ration,35,15,20,600,veggy
+food ration,45,20,380,800,veggy
+K-ration,25,10,0,400,veggy
+C-ration,20,10,0,300,veggy
+tin,5,10,75,0,metal`
+	csv := mustMapCsv(data)
+
+	for csv.next() {
+		c := &Class{
+			Category:    Comestible,
+			Name:        csv.get("name"),
+			Price:       mustInt(csv.get("price")),
+			Weight:      mustInt(csv.get("weight")),
+			Probability: mustProb(csv.get("probability")),
+			Nutrition:   mustInt(csv.get("nutrition")),
+			Material:    Material(csv.get("material")),
+			Edible:      true,
+		}
+		classes[c.Name] = c
+	}
+	if csv.err != io.EOF {
+		panic(csv.err)
+	}
+}
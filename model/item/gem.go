@@ -0,0 +1,72 @@
+package item
+
+import "io"
+
+func init() {
+	// Gems and stones all weigh 1, except the gray stones. Several classes
+	// share each color; see gemShuffle. Most precious stones are shown with
+	// "stone" after their name, as "jade stone", so that's the name we use.
+	data := `name,price,weight,probability,material,appearance
+dilithium crystal,4500,1,2,gemstone,white gem
+diamond,4000,1,3,gemstone,white gem
+ruby,3500,1,4,gemstone,red gem
+jacinth stone,3250,1,3,gemstone,orange gem
+sapphire,3000,1,4,gemstone,blue gem
+black opal,2500,1,3,gemstone,black gem
+emerald,2500,1,5,gemstone,green gem
+turquoise stone,2000,1,6,gemstone,green gem
+citrine stone,1500,1,4,gemstone,yellow gem
+aquamarine stone,1500,1,6,gemstone,green gem
+amber stone,1000,1,8,gemstone,yellowish brown gem
+topaz stone,900,1,10,gemstone,yellowish brown gem
+jet stone,850,1,6,gemstone,black gem
+opal,800,1,12,gemstone,white gem
+chrysoberyl stone,700,1,8,gemstone,yellow gem
+garnet stone,700,1,12,gemstone,red gem
+amethyst stone,600,1,14,gemstone,violet gem
+jasper stone,500,1,15,gemstone,red gem
+fluorite stone,400,1,15,gemstone,violet gem
+obsidian stone,200,1,9,gemstone,black gem
+agate stone,200,1,12,gemstone,orange gem
+jade stone,300,1,10,gemstone,green gem
+worthless piece of white glass,0,1,77,glass,white gem
+worthless piece of blue glass,0,1,77,glass,blue gem
+worthless piece of red glass,0,1,77,glass,red gem
+worthless piece of yellowish brown glass,0,1,77,glass,yellowish brown gem
+worthless piece of orange glass,0,1,76,glass,orange gem
+worthless piece of yellow glass,0,1,77,glass,yellow gem
+worthless piece of black glass,0,1,76,glass,black gem
+worthless piece of green glass,0,1,77,glass,green gem
+worthless piece of violet glass,0,1,77,glass,violet gem
+luckstone,60,10,10,mineral,gray stone
+loadstone,1,500,10,mineral,gray stone
+touchstone,45,10,8,mineral,gray stone
+flint stone,1,10,10,mineral,gray stone
+rock,0,10,100,mineral,`
+	csv := mustMapCsv(data)
+
+	for csv.next() {
+		c := &Class{
+			Category:    Gem,
+			Name:        csv.get("name"),
+			Price:       mustInt(csv.get("price")),
+			Weight:      mustInt(csv.get("weight")),
+			Probability: mustProb(csv.get("probability")),
+			Material:    Material(csv.get("material")),
+		}
+		classes[c.Name] = c
+
+		a := csv.get("appearance")
+		if a == "" {
+			continue
+		}
+		if _, ok := gemShuffle.shared[a]; !ok {
+			gemShuffle.appearances = append(gemShuffle.appearances, a)
+		}
+		gemShuffle.shared[a] = append(gemShuffle.shared[a], c.Name)
+		gemShuffle.names = append(gemShuffle.names, c.Name)
+	}
+	if csv.err != io.EOF {
+		panic(csv.err)
+	}
+}
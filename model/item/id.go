@@ -0,0 +1,229 @@
+package item
+
+import "fmt"
+
+// group is the state of a shuffle in one game.
+type group struct {
+	*shuffle
+
+	// used are the appearances we've seen. If there are more appearances
+	// than classes in the group, only these are sure to belong to a class.
+	used map[string]bool
+}
+
+// inUse returns whether appearance a is known to belong to a class in this
+// game.
+func (g *group) inUse(a string) bool {
+	return g.bijective() || g.used[a]
+}
+
+// OnIdentify registers f to be called each time an appearance is identified,
+// whether we were told what it is or worked it out by elimination. c is the
+// appearance's shared Class, with its Name filled in.
+func (r *Registry) OnIdentify(f func(appearance string, c *Class)) {
+	r.onIdentify = append(r.onIdentify, f)
+}
+
+// Restrict narrows what the items with an appearance might be to the
+// candidates for which keep returns true, then works out what that tells us
+// about the other appearances in its group. If no candidates would be left,
+// nothing is changed and an error is returned.
+//
+// For example, if a shopkeeper offers us a price for a platinum wand that
+// only wands of death and wishing cost, we'd restrict "platinum wand" to
+// those two.
+func (r *Registry) Restrict(appearance string, keep func(*Class) bool) error {
+	cands, ok := r.candidates[appearance]
+	if !ok {
+		return fmt.Errorf("not a shuffled appearance: %q", appearance)
+	}
+	var kept []*Class
+	for _, c := range cands {
+		if keep(c) {
+			kept = append(kept, c)
+		}
+	}
+	if len(kept) == 0 {
+		return fmt.Errorf("nothing left that %q could be", appearance)
+	}
+	r.candidates[appearance] = kept
+	r.propagate(r.groups[appearance])
+	return nil
+}
+
+// Exclude records that the items with an appearance are not of the class
+// with a name.
+//
+// Gems share their appearances rather than shuffling them, so knowing one
+// white gem is a diamond doesn't tell us about the others. Once we know what
+// diamonds are, though, nethack calls them by name, so from then on no white
+// gem is a diamond. Exclude is how to record that.
+func (r *Registry) Exclude(appearance, name string) error {
+	return r.Restrict(appearance, func(c *Class) bool { return c.Name != name })
+}
+
+// Identify records that the items with an appearance are of the class with a
+// name. The appearance's shared Class takes on all the properties of the
+// named class, and the name refers to the shared Class from now on.
+func (r *Registry) Identify(appearance, name string) error {
+	found := false
+	for _, c := range r.candidates[appearance] {
+		found = found || c.Name == name
+	}
+	if !found {
+		return fmt.Errorf("%q can't be %q", appearance, name)
+	}
+	r.inUse(appearance)
+	return r.Restrict(appearance, func(c *Class) bool { return c.Name == name })
+}
+
+// inUse records that we've seen an item with appearance a, so it must be one
+// of the classes in its group.
+func (r *Registry) inUse(a string) {
+	g := r.groups[a]
+	if g == nil || g.used[a] {
+		return
+	}
+	g.used[a] = true
+	r.propagate(g)
+}
+
+// propagate works out everything it can about the appearances in g from the
+// candidates they have left. Each class in a shuffled group has exactly one
+// of the group's appearances, which gives us these rules:
+//
+//   - If an appearance that's in use has one candidate left, that's what it
+//     is. (A "naked single".)
+//   - If k appearances in use have only k candidates between them, those
+//     classes can't be any other appearance. (A "naked subset". The naked
+//     single is the case k = 1, which removes an identified class from
+//     every other appearance.)
+//   - If only one appearance could be a class, it's that class. (A "hidden
+//     single".)
+//
+// Only the first rule applies to groups that share appearances. Appearances
+// that are still unidentified take on whatever their remaining candidates
+// have in common.
+func (r *Registry) propagate(g *group) {
+	for changed := true; changed; {
+		changed = false
+
+		for _, a := range g.appearances {
+			if cands := r.candidates[a]; len(cands) == 1 && g.inUse(a) && r.byAppearance[a].Name == "" {
+				r.settle(a, cands[0])
+				changed = true
+			}
+		}
+		if g.shared != nil {
+			continue
+		}
+
+		for _, a := range g.appearances {
+			if !g.inUse(a) {
+				continue
+			}
+			set := names(r.candidates[a])
+			var members []string
+			for _, b := range g.appearances {
+				if g.inUse(b) && subset(names(r.candidates[b]), set) {
+					members = append(members, b)
+				}
+			}
+			if len(members) != len(set) {
+				continue
+			}
+			for _, b := range g.appearances {
+				if !contains(members, b) && r.remove(b, set) {
+					changed = true
+				}
+			}
+		}
+
+		for _, n := range g.names {
+			var only string
+			count := 0
+			for _, a := range g.appearances {
+				if names(r.candidates[a])[n] {
+					only = a
+					count++
+				}
+			}
+			if count == 1 && len(r.candidates[only]) > 1 {
+				for _, c := range r.candidates[only] {
+					if c.Name == n {
+						r.candidates[only] = []*Class{c}
+					}
+				}
+				changed = true
+			}
+		}
+	}
+	for _, a := range g.appearances {
+		if c := r.byAppearance[a]; c.Name == "" {
+			share(c, r.candidates[a])
+		}
+	}
+}
+
+// remove removes the classes with the given names from the candidates for
+// appearance a. It returns whether anything was removed. If that would leave
+// no candidates, what we've been told is contradictory, and nothing is done.
+func (r *Registry) remove(a string, ns map[string]bool) bool {
+	var kept []*Class
+	for _, c := range r.candidates[a] {
+		if !ns[c.Name] {
+			kept = append(kept, c)
+		}
+	}
+	if len(kept) == 0 || len(kept) == len(r.candidates[a]) {
+		return false
+	}
+	r.candidates[a] = kept
+	return true
+}
+
+// settle makes the Class shared by appearance a into the class found, and
+// tells everyone who asked.
+func (r *Registry) settle(a string, found *Class) {
+	c := r.byAppearance[a]
+	called := c.Called
+	*c = *found
+	c.Appearance, c.Called = a, called
+	for n, nc := range r.byName {
+		if nc == found {
+			r.byName[n] = c
+		}
+	}
+	r.candidates[a] = []*Class{c}
+	for _, f := range r.onIdentify {
+		f(a, c)
+	}
+}
+
+// names returns the set of the classes' names.
+func names(cs []*Class) map[string]bool {
+	m := make(map[string]bool, len(cs))
+	for _, c := range cs {
+		m[c.Name] = true
+	}
+	return m
+}
+
+// subset returns whether every element of a is in b.
+func subset(a, b map[string]bool) bool {
+	for k := range a {
+		if !b[k] {
+			return false
+		}
+	}
+	return true
+}
+
+func contains(ss []string, s string) bool {
+	for _, x := range ss {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,125 @@
+package item
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// identified records the appearances a Registry reports as identified.
+func identified(r *Registry) map[string]string {
+	ids := make(map[string]string)
+	r.OnIdentify(func(a string, c *Class) { ids[a] = c.Name })
+	return ids
+}
+
+func candidateNames(r *Registry, a string) []string {
+	var ns []string
+	for _, c := range r.Candidates(a) {
+		ns = append(ns, c.Name)
+	}
+	return ns
+}
+
+func TestIdentifyEliminates(t *testing.T) {
+	r := NewRegistry()
+	ids := identified(r)
+
+	assert.Nil(t, r.Identify("tattered cape", "cloak of protection"))
+	assert.Equal(t, map[string]string{"tattered cape": "cloak of protection"}, ids)
+	assert.Len(t, r.Candidates("opera cloak"), 3)
+	assert.NotContains(t, candidateNames(r, "opera cloak"), "cloak of protection")
+
+	// Down to the last one: the naked single identifies itself, and the
+	// class it's left with is removed from the others.
+	assert.Nil(t, r.Exclude("opera cloak", "cloak of displacement"))
+	assert.Nil(t, r.Exclude("opera cloak", "cloak of magic resistance"))
+	assert.Equal(t, "cloak of invisibility", ids["opera cloak"])
+	assert.Equal(t, "cloak of invisibility", r.ByAppearance("opera cloak").Name)
+	assert.ElementsMatch(t, []string{"cloak of displacement", "cloak of magic resistance"},
+		candidateNames(r, "piece of cloth"))
+
+	// Contradictions are refused.
+	assert.NotNil(t, r.Restrict("ornamental cope", func(c *Class) bool { return false }))
+	assert.NotNil(t, r.Identify("ornamental cope", "cloak of protection"))
+	assert.NotNil(t, r.Restrict("runed dagger", func(c *Class) bool { return true }))
+}
+
+func TestHiddenSingle(t *testing.T) {
+	r := NewRegistry()
+	ids := identified(r)
+
+	// Only the piece of cloth can still be the cloak of displacement.
+	for _, a := range []string{"tattered cape", "opera cloak", "ornamental cope"} {
+		assert.Nil(t, r.Exclude(a, "cloak of displacement"))
+	}
+	assert.Equal(t, map[string]string{"piece of cloth": "cloak of displacement"}, ids)
+}
+
+func TestNakedSubset(t *testing.T) {
+	r := NewRegistry()
+	fast := func(c *Class) bool { return c.Name == "speed boots" || c.Name == "jumping boots" }
+	assert.Nil(t, r.Restrict("combat boots", fast))
+	assert.Nil(t, r.Restrict("hiking boots", fast))
+	for _, a := range []string{"jungle boots", "mud boots", "buckled boots", "riding boots", "snow boots"} {
+		assert.Len(t, r.Candidates(a), 5, a)
+		assert.NotContains(t, candidateNames(r, a), "speed boots", a)
+	}
+}
+
+// withShuffle adds s and its classes to the game data for the duration of a
+// test.
+func withShuffle(s *shuffle, cs ...*Class) func() {
+	shuffles = append(shuffles, s)
+	for _, c := range cs {
+		classes[c.Name] = c
+	}
+	return func() {
+		shuffles = shuffles[:len(shuffles)-1]
+		for _, c := range cs {
+			delete(classes, c.Name)
+		}
+	}
+}
+
+func TestSpareAppearances(t *testing.T) {
+	defer withShuffle(
+		&shuffle{Category: Wand, appearances: []string{"test1 wand", "test2 wand", "test3 wand"}, names: []string{"wand of x", "wand of y"}},
+		&Class{Category: Wand, Name: "wand of x"}, &Class{Category: Wand, Name: "wand of y"})()
+
+	r := NewRegistry()
+	ids := identified(r)
+
+	// An appearance with one candidate left may not be in use at all.
+	assert.Nil(t, r.Exclude("test1 wand", "wand of y"))
+	assert.Empty(t, ids)
+
+	// Once we've seen one, it must be the one.
+	_, err := r.Parse("a - a test1 wand")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"test1 wand": "wand of x"}, ids)
+	assert.Equal(t, []string{"wand of y"}, candidateNames(r, "test2 wand"))
+	assert.Equal(t, []string{"wand of y"}, candidateNames(r, "test3 wand"))
+	assert.Len(t, ids, 1)
+}
+
+func TestSharedAppearances(t *testing.T) {
+	defer withShuffle(
+		&shuffle{Category: Gem, appearances: []string{"test white gem", "test red gem"}, shared: map[string][]string{
+			"test white gem": {"test diamond", "test white glass"},
+			"test red gem":   {"test ruby", "test red glass"},
+		}},
+		&Class{Category: Gem, Name: "test diamond"}, &Class{Category: Gem, Name: "test white glass"},
+		&Class{Category: Gem, Name: "test ruby"}, &Class{Category: Gem, Name: "test red glass"})()
+
+	r := NewRegistry()
+	ids := identified(r)
+	assert.Len(t, r.Candidates("test white gem"), 2)
+
+	_, err := r.Parse("a - a test white gem")
+	assert.Nil(t, err)
+	assert.Empty(t, ids)
+	assert.Nil(t, r.Exclude("test white gem", "test diamond"))
+	assert.Equal(t, map[string]string{"test white gem": "test white glass"}, ids)
+	assert.Len(t, r.Candidates("test red gem"), 2)
+}
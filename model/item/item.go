@@ -3,6 +3,7 @@ package item
 import (
 	"fmt"
 	"strings"
+	"unicode"
 )
 
 // Item is an item in Nethack.
@@ -14,6 +15,11 @@ type Item struct {
 	// Fixed - has the item been fixed (rust/burn/corrode/rot-proofed?).
 	Fixed bool
 
+	// Material is what the item is made of, if its Class doesn't say. The
+	// material of some items, like wands, depends on their appearance, and
+	// we only learn it when nethack calls one rustproof or fireproof.
+	Material Material
+
 	// Greased - is the item greased?
 	Greased bool
 
@@ -36,17 +42,126 @@ type Item struct {
 	// The size of the stack of this item we have.
 	Stack int
 
-	// TODO(jaguilar): Figure out how to represent the candelabrum of invocation.
-	// Do we need a map of "special" properties? What about things like comestible
-	// age? Maybe an empty interface to support that kind of thing?
+	// Use is how the item is being used, if it's in our inventory.
+	Use
+
+	// Lit is set for a light source that's burning: "(lit)".
+	Lit bool
+
+	// PartlyUsed is set for candles that have burned for a while.
+	PartlyUsed bool
+
+	// Candles is the number of candles attached to the candelabrum of
+	// invocation: "(7 candles attached)".
+	Candles int
+
+	// Lock is the state of a box's lock, if we know it.
+	Lock
+
+	// Contents are the items in a container. They're only meaningful if
+	// ContentsKnown is set, which it is once we've looked inside, or
+	// nethack has told us the container is "empty".
+	Contents      []*Item
+	ContentsKnown bool
+
+	// Species is the name of the monster species a corpse, tin, egg, statue
+	// or figurine is of, if we know it. A tin of spinach has the Species
+	// "spinach".
+	Species string
+
+	// PartlyEaten is set for food we've started eating.
+	PartlyEaten bool
+
+	// Poisoned is set for poisoned missiles: "poisoned orcish arrows".
+	Poisoned bool
+
+	// Diluted is set for potions that have been dipped in water.
+	Diluted bool
+
+	// Died is our estimate of the turn a corpse's monster died, or 0 if we
+	// have no idea. Nethack doesn't show it: the Game fills it in for
+	// corpses it saw left by a kill, which needs the time option on.
+	Died int
+}
+
+// meatless are the species that nethack doesn't call the contents of a tin
+// of "meat".
+var meatless = map[string]bool{
+	"spinach": true, "lichen": true, "shrieker": true, "violet fungus": true,
+	"brown mold": true, "yellow mold": true, "green mold": true, "red mold": true,
+	"acid blob": true, "quivering blob": true, "gelatinous cube": true,
+	"blue jelly": true, "spotted jelly": true, "ochre jelly": true,
+	"gray ooze": true, "brown pudding": true, "green slime": true,
+}
+
+// speciesName returns the name of an item of a monster species, as nethack
+// would print it.
+func (i *Item) speciesName() string {
+	switch i.Class.Name {
+	case "tin":
+		if meatless[i.Species] {
+			return "tin of " + i.Species
+		}
+		return "tin of " + i.Species + " meat"
+	case "statue", "figurine":
+		article := "a "
+		switch {
+		case unicode.IsUpper([]rune(i.Species)[0]):
+			article = ""
+		case strings.ContainsRune("aeiou", []rune(i.Species)[0]):
+			article = "an "
+		}
+		return i.Class.Name + " of " + article + i.Species
+	}
+	return i.Species + " " + i.Class.Name
 }
 
-// String is part of the Stringer interface.
+// neverRot are the species whose corpses never go bad. Acid blob corpses
+// do rot, but nethack never makes them tainted: old ones only burn a bit.
+var neverRot = map[string]bool{"lichen": true, "lizard": true, "acid blob": true}
+
+// Fresh returns whether a corpse certainly hasn't gone bad at turn now. We
+// can only be sure of that if we know when its monster died. A corpse that
+// isn't fresh may be fine, but may also give fatal food poisoning.
 //
-// At the moment, we're not going to attempt to reach parity with Nethack's
-// printing of item names. Particularly, we're not bothering with the initial
-// article.
+// Nethack works out how rotten a corpse is by dividing its age by a random
+// number from 10 to 29. Blessed corpses count as 2 less rotten and cursed
+// ones as 2 more. Anything more than 5 rotten is tainted.
+func (i *Item) Fresh(now int) bool {
+	if neverRot[i.Species] {
+		return true
+	}
+	if i.Died == 0 {
+		return false
+	}
+	rotted := (now - i.Died) / 10
+	switch i.BUC {
+	case Blessed:
+		rotted -= 2
+	case Cursed:
+		rotted += 2
+	}
+	return rotted <= 5
+}
+
+// String returns the item the way nethack lists it in our inventory, with
+// its inventory letter if it has one: "a - an uncursed +0 pair of speed
+// boots (being worn)".
 func (i *Item) String() string {
+	if i.InventoryLetter != 0 {
+		return fmt.Sprintf("%c - %s", i.InventoryLetter, i.FullName())
+	}
+	return i.FullName()
+}
+
+// FullName returns the item's name with everything nethack's doname
+// function adds to it: its article or number, what we know of its BUC,
+// erosion and enchantment, and how it's being used. Parsing the FullName
+// gives back the same item.
+//
+// Nethack leaves out "uncursed" where its implicit_uncursed option lets it,
+// but we always show it. We can't tell whether nethack would have.
+func (i *Item) FullName() string {
 	var parts []string
 	writef := func(format string, args ...interface{}) {
 		s := fmt.Sprintf(format, args...)
@@ -55,71 +170,286 @@ func (i *Item) String() string {
 		}
 	}
 
-	if i.InventoryLetter != 0 {
-		writef("%c -", i.InventoryLetter)
+	switch {
+	case i.Stack > 1:
+		writef("%d", i.Stack)
+	case i.proper():
+		writef("the")
+	default:
+		writef("a")
 	}
 
-	if i.Stack > 1 {
-		writef("%d", i.Stack)
+	if i.ContentsKnown && len(i.Contents) == 0 {
+		writef("empty")
 	}
 
-	if i.BUC != BUCUnknown {
-		// Note: the model will print out its knowledge of the non-cursed state,
-		// even if nethack doesn't display that knowledge.
+	// Holy water's BUC is part of its name. Nethack doesn't show that we
+	// know an item is noncursed.
+	if i.BUC != BUCUnknown && i.BUC != Noncursed && !i.holyWater() {
 		writef("%s", strings.ToLower(i.BUC.String()))
 	}
 
+	writef("%s", lockNames[i.Lock])
+
 	if i.Greased {
 		writef("greased")
 	}
 
+	if i.PartlyUsed {
+		writef("partly used")
+	}
+
+	if i.PartlyEaten {
+		writef("partly eaten")
+	}
+
+	if i.Poisoned {
+		writef("poisoned")
+	}
+
 	writef("%s", i.Erosion.String())
 
 	if i.Fixed {
-		writef("%s", i.Class.Material.FixedString())
+		// Nethack only shows that an item is fixed if it could erode. If
+		// its material says it can't, we have it wrong, and fall back to
+		// the word that fits any material.
+		if f := i.material().FixedString(); f != "" {
+			writef("%s", f)
+		} else {
+			writef("fixed")
+		}
 	}
 
 	if i.Enhancement.Known {
 		writef("%s", i.Enhancement.String())
 	}
 
-	if !i.Artifact() {
-		switch {
-		case i.Class.Name != "":
-			// Case 1: it's identified. We print neither its appearance or its non-unique name.
-			writef("%s", i.Class.Name)
-		case i.Class.Called != "":
-			// Case 2: it's called something. Don't print its appearance.
-			writef("%s called %s", strings.ToLower(i.Class.Category.String()), i.Class.Called)
-		default:
-			// Case 3: if it's unidentified and uncalled, we print the appearance.
-			writef("%s %s", i.Class.Appearance, i.Class.Category)
-		}
+	writef("%s", i.Name())
+
+	if i.Charge.Known {
+		writef("%s", i.Charge.String())
+	}
 
-		// Whether identified, called, or uncalled, we print the named claused after.
-		if i.Named != "" {
-			writef("named %s", i.Class.Name)
+	switch {
+	case i.Class.Name == "Candelabrum of Invocation":
+		n := "no"
+		if i.Candles > 0 {
+			n = fmt.Sprint(i.Candles)
 		}
-	} else {
+		plural := ""
+		if i.Candles != 1 {
+			plural = "s"
+		}
+		state := " attached"
+		if i.Lit {
+			state = ", lit"
+		}
+		writef("(%s candle%s%s)", n, plural, state)
+	case i.Lit:
+		writef("(lit)")
+	}
+
+	switch {
+	case i.Use == Wielded && i.Stack > 1:
+		writef("(wielded)")
+	case i.Use == Wielded && i.Class.twoHanded():
+		writef("(weapon in hands)")
+	case i.Use != NotInUse:
+		writef("(%s)", useNames[i.Use])
+	}
+
+	if len(parts) > 1 && parts[0] == "a" && an(parts[1]) {
+		parts[0] = "an"
+	}
+	return strings.Join(parts, " ")
+}
+
+// Name returns the item's name the way nethack's xname function makes it:
+// what the item is, as far as we know, with what we've called or named it,
+// but without the article or adjectives that FullName adds. It's plural if
+// there's more than one: "potions called oil".
+func (i *Item) Name() string {
+	if i.Artifact() != nil {
 		// Identified artifacts are simply printed by their name. They can neither
 		// be called nor renamed (at least not in a way that's visible in the UI).
-		writef("%s", i.Named)
+		return i.Named
 	}
 
-	// Finally, the charge data, if any.
-	if i.Charge.Max != 0 {
-		writef("%s", i.Charge.String())
+	var name string
+	switch {
+	case i.Species != "":
+		// Case 0: it's of a monster species, which goes with its name.
+		name = i.speciesName()
+	case i.holyWater() && i.BUC == Blessed:
+		name = "potion of holy water"
+	case i.holyWater():
+		name = "potion of unholy water"
+	case i.Class.Name != "":
+		// Case 1: it's identified. We print neither its appearance or its non-unique name.
+		name = i.Class.Name
+	case i.Class.Called != "":
+		// Case 2: it's called something. Don't print its appearance.
+		name = i.Class.genericName() + " called " + i.Class.Called
+	default:
+		// Case 3: if it's unidentified and uncalled, we print the appearance.
+		// If we don't know that either, as when nethack describes items
+		// to a blind player, we print what kind of item it is.
+		name = i.Class.Appearance
+		if name == "" {
+			name = i.Class.genericName()
+		}
+	}
+	if i.Diluted {
+		name = "diluted " + name
+	}
+	if i.Species == "" && i.Class.pair() {
+		name = "pair of " + name
+	}
+	if i.Stack > 1 {
+		name = plural(name)
 	}
 
-	return strings.Join(parts, " ")
+	// Whether identified, called, or uncalled, we print the named clause after.
+	if i.Named != "" {
+		name += " named " + i.Named
+	}
+	return name
+}
+
+// holyWater returns whether the item is holy or unholy water, whose BUC
+// nethack shows as part of its name.
+func (i *Item) holyWater() bool {
+	return i.Class.Name == "potion of water" && (i.BUC == Blessed || i.BUC == Cursed)
+}
+
+// proper returns whether nethack calls the item "the" rather than "a": it's
+// an artifact, or the only one of its class.
+func (i *Item) proper() bool {
+	return i.Artifact() != nil || i.Class.Unique && i.Species == ""
+}
+
+// material returns what the item is made of: its class's material, or what
+// we've learned of this item's if the class doesn't say.
+func (i *Item) material() Material {
+	if i.Class.Material != "" {
+		return i.Class.Material
+	}
+	return i.Material
+}
+
+// an returns whether nethack puts "an" rather than "a" before word. Like
+// nethack, we only look at its first letter, except for a few names.
+func an(word string) bool {
+	for _, p := range []string{"unicorn", "uranium", "eucalyptus"} {
+		if strings.HasPrefix(word, p) {
+			return false
+		}
+	}
+	return strings.ContainsRune("aeiouAEIOU", []rune(word)[0])
+}
+
+// Weight returns the weight of the stack, including anything inside it.
+//
+// A bag of holding makes what's in it lighter: a half if it's uncursed, a
+// quarter if it's blessed. A cursed one makes it twice as heavy. If we don't
+// know the bag's BUC, we assume it's uncursed.
+func (i *Item) Weight() int {
+	n := i.Stack
+	if n == 0 {
+		n = 1
+	}
+	if i.Class.Category == Coins {
+		return (n + 50) / 100
+	}
+	w := i.Class.Weight * n
+
+	inside := 0
+	for _, c := range i.Contents {
+		inside += c.Weight()
+	}
+	if i.Class.Name == "bag of holding" {
+		switch i.BUC {
+		case Cursed:
+			inside *= 2
+		case Blessed:
+			inside = (inside + 3) / 4
+		default:
+			inside = (inside + 1) / 2
+		}
+	}
+	return w + inside
 }
 
-// Artifact returns whether item is an artifact. That is, are its name and class the same
-// as that of an artifact?
-func (i *Item) Artifact() bool {
-	return artifacts[artifactKey{name: i.Named, class: i.Class.Name}]
+// Artifact returns the artifact the item is, or nil if it isn't one. That is, are its
+// name and class the same as that of an artifact?
+func (i *Item) Artifact() *Artifact {
+	if a := artifacts[i.Named]; a != nil && a.Base == i.Class.Name {
+		return a
+	}
+	return nil
 }
 
+// Use is how an item in the inventory is being used. Nethack shows this in
+// parentheses after the item's name.
+type Use int
+
+// The various uses. Where nethack names a body part, it's the one for the
+// player's current form: "weapon in claw" is also Wielded.
+const (
+	// NotInUse - nothing is shown.
+	NotInUse Use = iota
+
+	// Worn - "(being worn)".
+	Worn
+
+	// Wielded - "(weapon in hand)", "(weapon in hands)" or "(wielded)".
+	Wielded
+
+	// WieldedOffhand - "(wielded in other hand)", when fighting with two
+	// weapons.
+	WieldedOffhand
+
+	// Alternate - "(alternate weapon; not wielded)".
+	Alternate
+
+	// Quivered - "(in quiver)", "(in quiver pouch)" or "(at the ready)".
+	Quivered
+
+	// LeftHand and RightHand are rings: "(on left hand)".
+	LeftHand
+	RightHand
+
+	// Embedded - dragon scales that have merged into the player's skin
+	// while polymorphed into a dragon.
+	Embedded
+)
+
+// useNames are how nethack shows each Use, for a player in their own form.
+var useNames = map[Use]string{
+	Worn:           "being worn",
+	Wielded:        "weapon in hand",
+	WieldedOffhand: "wielded in other hand",
+	Alternate:      "alternate weapon; not wielded",
+	Quivered:       "in quiver",
+	LeftHand:       "on left hand",
+	RightHand:      "on right hand",
+	Embedded:       "embedded in your skin",
+}
+
+// Lock is the state of a box's lock. Nethack only shows it once we've
+// tried to open the box, or looked at it with the #force command.
+type Lock int
+
+// The lock states.
+const (
+	LockUnknown Lock = iota
+	Locked
+	Unlocked
+	Broken
+)
+
+var lockNames = map[Lock]string{Locked: "locked", Unlocked: "unlocked", Broken: "broken"}
+
 // BUC is the blessed, cursed, or uncursed status of an item.
 // +gen stringer
 type BUC int
@@ -150,7 +480,7 @@ func (e Erosion) String() string {
 		return t
 	}
 
-	// Corrosion needs to be displayed before rust. Burnt before rotted.
+	// Nethack shows rust or burns before corrosion or rot.
 	if e.Rusty != Uneroded {
 		parts = append(parts, withPrefix(e.Rusty, "rusty"))
 	}
@@ -210,17 +540,16 @@ func (e Enhancement) String() string {
 
 // Charge tells the charge state of an item. If the item is of a type that is
 // not chargeable, or if the charge is not known, Known will be set to false.
+// Nethack shows it as "(1:7)": the item has been recharged once, and has 7
+// charges left.
 type Charge struct {
-	Cur, Max     int
-	TimesCharged int
+	Known              bool
+	Recharged, Charges int
 }
 
 func (c Charge) String() string {
-	if c.Max == 0 && c.Cur == 0 {
+	if !c.Known {
 		return "unknown"
 	}
-	if c.Max < c.Cur {
-		return "charged"
-	}
-	return fmt.Sprintf("(%d:%d)", c.Cur, c.Max)
+	return fmt.Sprintf("(%d:%d)", c.Recharged, c.Charges)
 }
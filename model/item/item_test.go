@@ -12,23 +12,27 @@ func TestItemStringer(t *testing.T) {
 	// The input is exactly how nethack formats this item.
 	i, err := Parse("A - an uncursed very burnt rotted +0 elven cloak")
 	if assert.Nil(t, err) {
-		// Note: article dropped intentionally.
-		assert.Equal(t, "A - uncursed very burnt rotted +0 elven cloak", i.String(), "%#v", i)
+		assert.Equal(t, "A - an uncursed very burnt rotted +0 elven cloak", i.String(), "%#v", i)
 	}
 }
 
 // TestItemParseStringParse is the big daddy item regression test. We've selected
 // a large number of identified items from dumplogs at the alt.org nethack server.
-// We do not expect for Parse().String() to match the input string. However, it
-// should be the case that any time we parse the stringified version of an Item,
-// the resulting item is the same. Any time that doesn't happen, it's a bug and needs
-// to be addressed.
+// Parse().String() should give back the input string, and parsing the stringified
+// version of an Item should give back the same item. Any time that doesn't happen,
+// it's a bug and needs to be addressed.
 //
 // The items in this regression test are by no means unique. Once the bot is able
 // to play the game, we will begin assembling items it finds into a go-fuzz corpus.
 // That corpus will become an exhaustive test of our parsing capabilities.
+// headings are the inventory's category headings, which are in the dumplogs
+// too.
+var headings = map[string]bool{
+	"Amulets": true, "Weapons": true, "Armor": true, "Comestibles": true, "Scrolls": true,
+	"Spellbooks": true, "Potions": true, "Rings": true, "Wands": true, "Tools": true, "Gems": true,
+}
+
 func TestItemParseRegression(t *testing.T) {
-	t.Skip("Cannot yet pass. Need to have the full item corpus first.")
 	assert := assert.New(t)
 	f, err := os.Open("_testdata/item_regression_test_data.txt")
 	if !assert.Nil(err) {
@@ -39,11 +43,15 @@ func TestItemParseRegression(t *testing.T) {
 
 	for scanner.Scan() {
 		s := scanner.Text()
+		if headings[s] {
+			continue
+		}
 		i, err := Parse(s)
 		if !assert.Nil(err, "parse failed: %s", s) {
 			continue
 		}
 		s2 := i.String()
+		assert.Equal(s, s2)
 		i2, err := Parse(s2)
 
 		if !assert.Nil(err, "reparse failed: %s orig: %s", s2, s) {
@@ -59,3 +67,133 @@ func TestItemParseRegression(t *testing.T) {
 	}
 	assert.Nil(scanner.Err())
 }
+
+func TestToolStringRoundTrip(t *testing.T) {
+	for _, s := range []string{
+		"a - an empty uncursed sack",
+		"b - a locked large box",
+		"c - a blessed partly used wax candle (lit)",
+		"d - the Candelabrum of Invocation (7 candles, lit)",
+		"d - the Candelabrum of Invocation (1 candle attached)",
+		"d - the Candelabrum of Invocation (no candles attached)",
+		"e - an uncursed magic marker (0:50)",
+		"f - an oil lamp (lit)",
+	} {
+		i, err := Parse(s)
+		if assert.Nil(t, err, s) {
+			assert.Equal(t, s, i.String())
+		}
+	}
+}
+
+func TestStringRoundTrip(t *testing.T) {
+	for _, s := range []string{
+		"a - 3 daggers",
+		"b - 2 pairs of speed boots (being worn)",
+		"c - 5 potions of extra healing",
+		"d - 14 uncursed rubies",
+		"e - 2 uncursed worthless pieces of green glass",
+		"f - 2 knives",
+		"g - 35 blessed poisoned rustproof +7 crossbow bolts (in quiver)",
+		"h - 2 blessed diluted potions of healing",
+		"i - 3 potions of holy water",
+		"i - a potion of unholy water",
+		"j - a blessed +2 silver saber (weapon in hand)",
+		"k - an uncursed ring of free action (on left hand)",
+		"l - 2 potions called oil",
+		"m - 7 uncursed scrolls of teleportation named c",
+		"n - a wand of striking (1:4)",
+		"o - 2 newt corpses",
+		"p - 2 tins of spinach",
+		"q - a unicorn horn",
+		"r - an Uruk-hai shield",
+		"s - a K-ration",
+		"t - the Amulet of Yendor",
+		"t - a cheap plastic imitation of the Amulet of Yendor",
+		"u - a pair of boots called speedy",
+		"v - a stone called gray",
+		"w - 3 daggers (wielded)",
+		"x - a blessed +2 quarterstaff (weapon in hands)",
+		"y - the blessed rustproof +7 Stormbringer (weapon in hand)",
+		"z - a blessed fireproof +0 pair of speed boots (being worn)",
+		"A - a wand of cold (0:0)",
+		"B - a fireproof oak wand",
+		// What nethack calls items a blind player hasn't seen.
+		"C - a potion",
+		"D - a gem",
+		"E - 2 scrolls",
+		"F - an amulet",
+		"G - a stone",
+	} {
+		i, err := Parse(s)
+		if assert.Nil(t, err, s) {
+			assert.Equal(t, s, i.String())
+		}
+	}
+}
+
+func TestSpeciesStringRoundTrip(t *testing.T) {
+	for _, s := range []string{
+		"a - an uncursed partly eaten lichen corpse",
+		"c - a tin of floating eye meat",
+		"c - a tin of spinach",
+		"c - a tin of lichen",
+		"d - a statue of a soldier ant",
+		"d - a figurine of an owlbear",
+		"d - a figurine of Medusa",
+		"e - a cockatrice egg",
+	} {
+		i, err := Parse(s)
+		if assert.Nil(t, err, s) {
+			assert.Equal(t, s, i.String())
+		}
+	}
+}
+
+func TestName(t *testing.T) {
+	for _, tc := range []struct{ s, name string }{
+		{"a - an uncursed +0 pair of speed boots (being worn)", "pair of speed boots"},
+		{"b - 2 blessed potions of holy water", "potions of holy water"},
+		{"c - 2 uncursed potions called oil", "potions called oil"},
+		{"d - a blessed +2 elven dagger named Bob", "elven dagger named Bob"},
+		{"e - a tin of newt meat", "tin of newt meat"},
+	} {
+		i, err := Parse(tc.s)
+		if assert.Nil(t, err, tc.s) {
+			assert.Equal(t, tc.name, i.Name())
+		}
+	}
+	assert.Equal(t, "a partly eaten newt corpse", (&Item{Class: classes["corpse"], Species: "newt", PartlyEaten: true}).FullName())
+	assert.Equal(t, "an egg", (&Item{Class: classes["egg"]}).FullName())
+}
+
+func TestFresh(t *testing.T) {
+	newt := &Item{Class: classes["corpse"], Species: "newt", Died: 100}
+	assert.True(t, newt.Fresh(150))
+	assert.False(t, newt.Fresh(160))
+	newt.BUC = Blessed
+	assert.True(t, newt.Fresh(170))
+	newt.BUC = Cursed
+	assert.False(t, newt.Fresh(140))
+
+	// We can't tell how old a corpse is unless we saw it die.
+	assert.False(t, (&Item{Class: classes["corpse"], Species: "newt"}).Fresh(100))
+	assert.True(t, (&Item{Class: classes["corpse"], Species: "lichen"}).Fresh(100000))
+	assert.True(t, (&Item{Class: classes["corpse"], Species: "acid blob", Died: 100}).Fresh(100000))
+}
+
+func TestWeight(t *testing.T) {
+	rock := &Item{Class: classes["rock"], Stack: 10}
+	assert.Equal(t, 100, rock.Weight())
+	assert.Equal(t, 1, (&Item{Class: &Class{Category: Coins}, Stack: 100}).Weight())
+
+	bag := &Item{Class: classes["bag of holding"], Contents: []*Item{rock}}
+	assert.Equal(t, 15+50, bag.Weight())
+	bag.BUC = Blessed
+	assert.Equal(t, 15+25, bag.Weight())
+	bag.BUC = Cursed
+	assert.Equal(t, 15+200, bag.Weight())
+
+	sack := &Item{Class: classes["sack"], Contents: []*Item{rock, bag}}
+	assert.Equal(t, 15+100+215, sack.Weight())
+}
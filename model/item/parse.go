@@ -4,32 +4,65 @@ import (
 	"fmt"
 	"regexp"
 	"strconv"
+	"strings"
+	"unicode"
 )
 
 // Parse parses an item string and returns an appropriate *Item for that string.
+// The Item gets a Class of its own, filled in from what we know of the game's
+// items in general. To share Classes between the items in a game, use a
+// Registry's Parse.
 func Parse(s string) (*Item, error) {
+	return parse(s, nil, 0)
+}
+
+// ParseIn is like Parse, for an item listed under the heading of category
+// cat, as in the inventory. An item that's only shown by a proper name, so
+// that we can't tell its class, is taken to be of cat.
+func ParseIn(s string, cat Category) (*Item, error) {
+	return parse(s, nil, cat)
+}
+
+// parse parses an item string, getting its Class from r. cat is the
+// category of an item whose class we can't tell, if we know it.
+func parse(s string, r *Registry, cat Category) (*Item, error) {
 	m := matchMap(itemRe, itemRe.FindStringSubmatch(s))
 	if m == nil {
-		return nil, fmt.Errorf("mismatch vs item regexp: %s", s)
+		return nil, fmt.Errorf("no item name in %q", s)
 	}
 
-	i := &Item{Class: &Class{}}
-	if t, ok := m["type"]; ok {
-		i.Class.Name = t
+	i := &Item{Stack: 1}
+	switch o := m["ordinal"]; o {
+	case "", "a", "an", "the":
+	default:
+		i.Stack, _ = strconv.Atoi(o)
 	}
 
-	if c, ok := m["called"]; ok {
-		i.Class.Called = c
+	desc := m["type"]
+	if i.Stack > 1 {
+		desc = r.singular(desc)
+	}
+	desc, i.BUC = water(desc, parseBUC(m["buc"]))
+	desc, i.Species = splitSpecies(desc)
+	i.Class = r.classFor(desc, m["called"])
+	if a := ArtifactNamed(desc); i.Class == nil && a != nil {
+		// Nethack shows an identified artifact by its name alone.
+		i.Class, i.Named = r.classFor(a.Base, ""), a.Name
+	}
+	if i.Class == nil {
+		if !unicode.IsUpper([]rune(desc)[0]) {
+			return nil, fmt.Errorf("unknown item %q in %q", desc, s)
+		}
+		// A proper name, like an artifact's, is all nethack shows of an
+		// item that has one. We don't know what class it's of, but we take
+		// it to be one of a kind.
+		i.Class = &Class{Name: desc, Category: cat, Called: m["called"], Unique: true}
 	}
 
 	if n, ok := m["named"]; ok {
 		i.Named = n
 	}
 
-	if bucStr, ok := m["buc"]; ok {
-		i.BUC = parseBUC(bucStr)
-	}
-
 	if enhStr, ok := m["enh"]; ok {
 		i.Enhancement.Known = true
 		if _, err := fmt.Sscanf(enhStr, "%d", &i.Enhancement.Value); err != nil {
@@ -37,31 +70,70 @@ func Parse(s string) (*Item, error) {
 		}
 	}
 
-	if charge, ok := m["charge"]; ok {
-		maxCharge, ok := m["maxcharge"]
-		if !ok {
-			return nil, fmt.Errorf("maxcharge not set but charge is: %s", s)
-		}
-		var err error
-		i.Charge.Cur, err = strconv.Atoi(charge)
-		if err != nil {
-			return nil, err
-		}
-		i.Charge.Max, err = strconv.Atoi(maxCharge)
-		if err != nil {
-			return nil, err
-		}
+	if recharged, ok := m["recharged"]; ok {
+		i.Charge.Known = true
+		// The regexp only matches numbers.
+		i.Charge.Recharged, _ = strconv.Atoi(recharged)
+		i.Charge.Charges, _ = strconv.Atoi(m["charges"])
+	}
+
+	if _, ok := m["empty"]; ok {
+		i.ContentsKnown = true
+	}
+
+	switch m["lock"] {
+	case "locked":
+		i.Lock = Locked
+	case "unlocked":
+		i.Lock = Unlocked
+	case "broken":
+		i.Lock = Broken
+	}
+
+	if _, ok := m["greased"]; ok {
+		i.Greased = true
+	}
+
+	switch m["partly"] {
+	case "partly used":
+		i.PartlyUsed = true
+	case "partly eaten":
+		i.PartlyEaten = true
+	}
+
+	if _, ok := m["poisoned"]; ok {
+		i.Poisoned = true
+	}
+
+	if _, ok := m["diluted"]; ok {
+		i.Diluted = true
 	}
 
-	if _, ok := m["fixedness"]; ok {
+	if l, ok := m["light"]; ok {
+		i.Lit = strings.HasSuffix(l, "lit")
+		// "no candles" leaves Candles at 0.
+		fmt.Sscanf(l, "%d", &i.Candles)
+	}
+
+	if f, ok := m["fixedness"]; ok {
 		// The only way this turns up is if the item is indeed fixed.
 		i.Fixed = true
+		// The material of some items, like wands, depends on their
+		// appearance. Whether they're rustproof or fireproof tells us what
+		// it is.
+		if i.Class.Material == "" {
+			i.Material = proofMaterials[f]
+		}
 	}
 
 	if erosion, ok := m["erosions"]; ok {
 		i.Erosion = parseErosion(erosion)
 	}
 
+	if u, ok := m["use"]; ok {
+		i.Use = parseUse(u)
+	}
+
 	if invLetter, ok := m["slot"]; ok {
 		i.InventoryLetter = []rune(invLetter)[0]
 	}
@@ -69,6 +141,57 @@ func Parse(s string) (*Item, error) {
 	return i, nil
 }
 
+var (
+	// corpseRe matches corpses and eggs: "newt corpse", "cockatrice eggs".
+	corpseRe = regexp.MustCompile(`^(.+) (corpse|egg)s?$`)
+
+	// tinRe matches tins whose contents we know: "tin of newt meat", "tin
+	// of lichen", "tin of spinach".
+	tinRe = regexp.MustCompile(`^(tin)s? of (.+?)(?: meat)?$`)
+
+	// figureRe matches statues and figurines: "statue of a soldier ant",
+	// "figurine of Medusa".
+	figureRe = regexp.MustCompile(`^(statue|figurine)s? of (?:an? |the )?(.+)$`)
+)
+
+// proofMaterials are the materials that items nethack calls rustproof,
+// fireproof and so on might be made of. An item of unknown material is
+// taken to be made of the first.
+var proofMaterials = map[string]Material{
+	"fixed":        Mineral,
+	"rustproof":    Iron,
+	"corrodeproof": Copper,
+	"fireproof":    Wood,
+}
+
+// water returns the description of a potion of (un)holy water as plain
+// water, and its BUC, which nethack doesn't show separately. Anything else
+// is returned as it is.
+func water(desc string, b BUC) (string, BUC) {
+	switch desc {
+	case "potion of holy water":
+		return "potion of water", Blessed
+	case "potion of unholy water":
+		return "potion of water", Cursed
+	}
+	return desc, b
+}
+
+// splitSpecies splits an item description that names a monster species into
+// the item's class name and the species: "newt corpse" is a "corpse" of
+// species "newt". Other descriptions are returned as they are.
+func splitSpecies(desc string) (class, species string) {
+	if m := corpseRe.FindStringSubmatch(desc); m != nil {
+		return m[2], m[1]
+	}
+	for _, re := range []*regexp.Regexp{tinRe, figureRe} {
+		if m := re.FindStringSubmatch(desc); m != nil {
+			return m[1], m[2]
+		}
+	}
+	return desc, ""
+}
+
 // matchMap returns a map of each subexpression name to its match, if any.
 // A nil return value indicates no match.
 func matchMap(re *regexp.Regexp, s []string) map[string]string {
@@ -97,40 +220,43 @@ func matchMap(re *regexp.Regexp, s []string) map[string]string {
 // During the course of this regexp, we capture a little more than we need to in
 // some places.
 var (
-	slot = "^(?:(?P<slot>[a-zA-Z]) -)?"
+	slot = "^(?:(?P<slot>[a-zA-Z$#]) -)?"
 	// Ordinal is optional so as to support re-parsing.
-	ordinal = `\W?(?P<ordinal>an|a|the|\d{1,2})?`
-	buc     = `\W?(?P<buc>blessed|uncursed|cursed)?`
-	greased = `\W?(?P<greased>greased)?`
-	erosion = `\W?(?P<erosion>(?P<elevel>thoroughly|very)?\W?(?P<etype>rusty|burnt|corroded|rotted))`
+	ordinal  = ` ?(?P<ordinal>an|a|the|\d+)?`
+	empty    = ` ?(?P<empty>empty)?`
+	buc      = ` ?(?P<buc>blessed|uncursed|cursed)?`
+	lock     = ` ?(?P<lock>unlocked|locked|broken)?`
+	greased  = ` ?(?P<greased>greased)?`
+	partly   = ` ?(?P<partly>partly (?:used|eaten))?`
+	poisoned = ` ?(?P<poisoned>poisoned)?`
+	erosion  = ` ?(?P<erosion>(?P<elevel>thoroughly|very)? ?(?P<etype>rusty|burnt|corroded|rotted))`
 
 	// Once we have the erosions string, we'll have to search it with the erosion regexp to parse
 	// out each individual erosion.
 	erosions = `(?P<erosions>(?:` + erosion + `)*)`
 
-	fixedness = `\W?(?P<fixedness>(?:\w*proof)|fixed)?`
-	enh       = `\W?(?P<enh>(?:\+|-)\d)?`
-	itype     = `\W?(?P<type>[a-zA-Z ]+?)`
-	called    = `(?:\W?called (?P<called>.+?))?`
-	named     = `(?:\W?named (?P<named>.+?))?`
+	fixedness = ` ?(?P<fixedness>(?:\w*proof)|fixed)?`
+	enh       = ` ?(?P<enh>(?:\+|-)\d+)?`
+	diluted   = ` ?(?P<diluted>diluted)?`
+	itype     = ` ?(?P<type>[a-zA-Z](?:[a-zA-Z0-9 -]*?[a-zA-Z0-9])?)`
+	called    = `(?: ?called (?P<called>.+?))?`
+	named     = `(?: ?named (?P<named>.+?))?`
 	// Match charge info.
-	charge = `\W?(?:\((?P<charge>\d{1,3}):(?P<maxcharge>\d{1,3})\))?`
-	// Match any non-charge property. Currently ignored. This will match things
-	// like the candelabrum of invocation's extra information.
-	otherParenProperty = `\W?(?P<paren>(?:\W?\([^)]*\)*?))?$`
-
-	/*
-	   Note: we don't take whether the item is being worn into account here.
-	   That is information about the item's location in the world, which is not part of
-	   parsing the item.
-	   	worn = `(?: \((?P<worn>` + alternates(
-	   		"being worn", "weapon in \\w*", "in quiver",
-	   		"alternate weapon; not wielded", "wielded in other \\w*",
-	   		"on \\w* \\w*") + `)\))?`
-	*/
+	charge = ` ?(?:\((?P<recharged>-?\d{1,3}):(?P<charges>-?\d{1,3})\))?`
+	// Match whether a light source is lit, and the candelabrum's candles.
+	light = `(?: ?\((?P<light>lit|(?:no|\d) candles?(?: attached|, lit))\))?`
+	// Match any other property in parentheses. Currently ignored.
+	otherParenProperty = ` ?(?P<paren>(?: ?\([^)]*\)*?))?$`
+
+	// Match how the item is being used, if it's in our inventory. See Use.
+	use = `(?: ?\((?P<use>being worn|embedded in your skin|(?:tethered )?weapon in \w+|` +
+		`wielded(?: in other \w+)?|alternate weapon; not wielded|in quiver(?: pouch)?|at the ready|` +
+		`on (?:left|right) \w+)\))?`
 
 	itemRe = regexp.MustCompile(
-		slot + ordinal + buc + greased + erosions + fixedness + enh + itype + called + named + charge + otherParenProperty)
+		slot + ordinal + empty + buc + lock + greased + partly + poisoned + erosions + fixedness + enh + diluted +
+			itype +
+			called + named + charge + light + use + otherParenProperty)
 
 	erosionRe = regexp.MustCompile(erosion)
 )
@@ -151,6 +277,29 @@ func parseBUC(s string) BUC {
 	}
 }
 
+// parseUse parses one of the use annotations matched by the use regexp.
+func parseUse(s string) Use {
+	switch {
+	case s == "being worn":
+		return Worn
+	case s == "embedded in your skin":
+		return Embedded
+	case s == "alternate weapon; not wielded":
+		return Alternate
+	case strings.HasPrefix(s, "wielded in other"):
+		return WieldedOffhand
+	case s == "wielded" || strings.Contains(s, "weapon in"):
+		return Wielded
+	case strings.HasPrefix(s, "in quiver") || s == "at the ready":
+		return Quivered
+	case strings.HasPrefix(s, "on left"):
+		return LeftHand
+	case strings.HasPrefix(s, "on right"):
+		return RightHand
+	}
+	return NotInUse
+}
+
 func parseErosion(s string) Erosion {
 	var e Erosion
 	matches := erosionRe.FindAllStringSubmatch(s, -1)
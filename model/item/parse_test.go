@@ -7,9 +7,19 @@ import (
 )
 
 func TestFailedParses(t *testing.T) {
-	// TODO(jaguilar): until we have a table of the items in the game, we're
-	// not realistically going to be able to fail any tests. This is because the
-	// match that captures the item type is very broad.
+	for _, tc := range []struct {
+		s, err string
+	}{
+		{"", `no item name in ""`},
+		{"a - (lit)", `no item name in "a - (lit)"`},
+		{"a - a blessed +2 flugelhorn", `unknown item "flugelhorn" in "a - a blessed +2 flugelhorn"`},
+		{"b - 3 uncursed widgets", `unknown item "widgets" in "b - 3 uncursed widgets"`},
+	} {
+		_, err := Parse(tc.s)
+		if assert.NotNil(t, err, tc.s) {
+			assert.Equal(t, tc.err, err.Error())
+		}
+	}
 }
 
 func mustParse(s string) *Item {
@@ -39,7 +49,7 @@ func TestParseCharge(t *testing.T) {
 		string
 		Charge
 	}{
-		{"a - a blessed wand of death (1:7)", Charge{Cur: 1, Max: 7}},
+		{"a - a blessed wand of death (1:7)", Charge{Known: true, Recharged: 1, Charges: 7}},
 		{"b - a blessed wand of death", Charge{}},
 	} {
 		i, err := Parse(tc.string)
@@ -54,8 +64,8 @@ func TestParseErosions(t *testing.T) {
 		string
 		Erosion
 	}{
-		{"a - a thoroughly rusty iron helm", Erosion{Rusty: ThoroughlyEroded}},
-		{"b - an iron helm", Erosion{}},
+		{"a - a thoroughly rusty dwarvish iron helm", Erosion{Rusty: ThoroughlyEroded}},
+		{"b - a dwarvish iron helm", Erosion{}},
 		{"c - a very rotted elven cloak", Erosion{Rotted: VeryEroded}},
 		{"d - a blessed burnt fireproof +5 pair of water walking boots", Erosion{Burnt: Eroded}},
 		{"e - a thoroughly corroded -3 orcish dagger named puddingbane", Erosion{Corroded: ThoroughlyEroded}},
@@ -73,8 +83,8 @@ func TestParseFixedness(t *testing.T) {
 		string
 		bool
 	}{
-		{"a - a thoroughly rusty iron helm", false},
-		{"b - a fixed iron helm", true},
+		{"a - a thoroughly rusty dwarvish iron helm", false},
+		{"b - a fixed dwarvish iron helm", true},
 		{"c - a very rotted elven cloak", false},
 		{"d - a blessed burnt fireproof +5 pair of water walking boots", true},
 		{"e - a thoroughly corroded -3 orcish dagger named puddingbane", false},
@@ -88,7 +98,7 @@ func TestParseFixedness(t *testing.T) {
 }
 
 func TestParseSlot(t *testing.T) {
-	assert.Equal(t, 'V', mustParse("V - a shortsword").InventoryLetter)
+	assert.Equal(t, 'V', mustParse("V - a short sword").InventoryLetter)
 }
 
 func TestNamed(t *testing.T) {
@@ -98,3 +108,157 @@ func TestNamed(t *testing.T) {
 func TestCalled(t *testing.T) {
 	assert.Equal(t, "sickness", mustParse("e - a potion called sickness").Class.Called)
 }
+
+func TestParseStack(t *testing.T) {
+	assert.Equal(t, 1, mustParse("a - an uncursed orcish dagger").Stack)
+	assert.Equal(t, 12, mustParse("b - 12 uncursed darts (in quiver)").Stack)
+	assert.Equal(t, '$', mustParse("$ - 12 gold pieces").InventoryLetter)
+}
+
+func TestParseUse(t *testing.T) {
+	for _, tc := range []struct {
+		string
+		Use
+	}{
+		{"a - a +1 long sword (weapon in hand)", Wielded},
+		{"a - a +1 long sword (weapon in claw)", Wielded},
+		{"b - an uncursed +0 leather armor (being worn)", Worn},
+		{"c - 12 +2 darts (in quiver)", Quivered},
+		{"d - an uncursed ring of free action (on left hand)", LeftHand},
+		{"e - a blessed +1 elven dagger (alternate weapon; not wielded)", Alternate},
+		{"f - a dagger named sting (wielded in other hand)", WieldedOffhand},
+		{"g - a wand of digging (0:5)", NotInUse},
+		{"h - an oil lamp (lit)", NotInUse},
+	} {
+		i, err := Parse(tc.string)
+		if assert.Nil(t, err, tc.string) {
+			assert.Equal(t, tc.Use, i.Use, tc.string)
+		}
+	}
+	assert.Equal(t, "sting", mustParse("f - a dagger named sting (wielded in other hand)").Named)
+	assert.Equal(t, Charge{Known: true, Recharged: 0, Charges: 5}, mustParse("g - a wand of digging (0:5)").Charge)
+}
+
+func TestParseTools(t *testing.T) {
+	i := mustParse("a - an empty uncursed sack")
+	assert.True(t, i.ContentsKnown)
+	assert.Equal(t, Uncursed, i.BUC)
+	assert.Equal(t, "sack", i.Class.Name)
+
+	i = mustParse("b - a locked large box")
+	assert.Equal(t, Locked, i.Lock)
+	assert.Equal(t, "large box", i.Class.Name)
+	assert.Equal(t, Unlocked, mustParse("b - an unlocked chest").Lock)
+	assert.Equal(t, Broken, mustParse("b - a broken chest").Lock)
+
+	i = mustParse("c - a blessed partly used wax candle (lit)")
+	assert.True(t, i.PartlyUsed)
+	assert.True(t, i.Lit)
+	assert.Equal(t, "wax candle", i.Class.Name)
+
+	i = mustParse("d - the Candelabrum of Invocation (7 candles, lit)")
+	assert.Equal(t, 7, i.Candles)
+	assert.True(t, i.Lit)
+	i = mustParse("d - the Candelabrum of Invocation (1 candle attached)")
+	assert.Equal(t, 1, i.Candles)
+	assert.False(t, i.Lit)
+	assert.Equal(t, 0, mustParse("d - the Candelabrum of Invocation (no candles attached)").Candles)
+
+	i = mustParse("e - an uncursed magic marker (0:50)")
+	assert.Equal(t, Charge{Known: true, Recharged: 0, Charges: 50}, i.Charge)
+	assert.True(t, i.Class.Charged)
+
+	assert.True(t, mustParse("f - a greased +0 leather cloak").Greased)
+}
+
+func TestParseSpecies(t *testing.T) {
+	for _, tc := range []struct {
+		s, class, species string
+	}{
+		{"a - a lichen corpse", "corpse", "lichen"},
+		{"b - 2 partly eaten newt corpses", "corpse", "newt"},
+		{"c - a tin of floating eye meat", "tin", "floating eye"},
+		{"c - a tin of spinach", "tin", "spinach"},
+		{"c - a tin", "tin", ""},
+		{"d - a statue of a soldier ant", "statue", "soldier ant"},
+		{"d - a figurine of Medusa", "figurine", "Medusa"},
+		{"e - an egg", "egg", ""},
+		{"e - a cockatrice egg", "egg", "cockatrice"},
+	} {
+		i := mustParse(tc.s)
+		assert.Equal(t, tc.class, i.Class.Name, tc.s)
+		assert.Equal(t, tc.species, i.Species, tc.s)
+	}
+	i := mustParse("b - 2 partly eaten newt corpses")
+	assert.True(t, i.PartlyEaten)
+	assert.Equal(t, 2, i.Stack)
+	assert.Equal(t, Statue, mustParse("d - a statue of a soldier ant").Class.Category)
+	assert.Equal(t, Boulder, mustParse("f - a boulder").Class.Category)
+}
+
+func TestParsePlurals(t *testing.T) {
+	for _, tc := range []struct {
+		s     string
+		stack int
+		class string
+	}{
+		{"a - 3 daggers", 3, "dagger"},
+		{"b - 2 pairs of speed boots", 2, "speed boots"},
+		{"c - 5 potions of extra healing", 5, "potion of extra healing"},
+		{"d - 14 uncursed rubies", 14, "ruby"},
+		{"e - 2 uncursed worthless pieces of green glass", 2, "worthless piece of green glass"},
+		{"f - 2 knives", 2, "knife"},
+		{"g - 2 quarterstaves", 2, "quarterstaff"},
+		{"h - 3 eucalyptus leaves", 3, "eucalyptus leaf"},
+		{"i - 2 uncursed K-rations", 2, "K-ration"},
+		{"j - 9 uncursed lumps of royal jelly", 9, "lump of royal jelly"},
+		{"115772 gold pieces", 115772, "gold piece"},
+	} {
+		i, err := Parse(tc.s)
+		if assert.Nil(t, err, tc.s) {
+			assert.Equal(t, tc.stack, i.Stack, tc.s)
+			assert.Equal(t, tc.class, i.Class.Name, tc.s)
+		}
+	}
+
+	r := NewRegistry()
+	i, err := r.Parse("k - 5 scrolls labeled KIRJE")
+	if assert.Nil(t, err) {
+		assert.True(t, i.Class == r.ByAppearance("scroll labeled KIRJE"))
+	}
+	i, err = r.Parse("l - 2 potions called oil")
+	if assert.Nil(t, err) {
+		assert.Equal(t, Potion, i.Class.Category)
+		assert.Equal(t, "oil", i.Class.Called)
+	}
+}
+
+func TestParseQualifiers(t *testing.T) {
+	i := mustParse("a - 35 blessed poisoned rustproof +7 crossbow bolts (in quiver)")
+	assert.True(t, i.Poisoned)
+	assert.True(t, i.Fixed)
+	assert.Equal(t, Enhancement{Known: true, Value: 7}, i.Enhancement)
+	assert.Equal(t, Quivered, i.Use)
+	assert.Equal(t, "crossbow bolt", i.Class.Name)
+
+	i = mustParse("b - 2 blessed diluted potions of healing")
+	assert.True(t, i.Diluted)
+	assert.Equal(t, Blessed, i.BUC)
+
+	i = mustParse("c - 3 potions of holy water")
+	assert.Equal(t, Blessed, i.BUC)
+	assert.Equal(t, "potion of water", i.Class.Name)
+	assert.Equal(t, Cursed, mustParse("c - a potion of unholy water").BUC)
+
+	i = mustParse("d - a +10 long sword (weapon in hands)")
+	assert.Equal(t, 10, i.Enhancement.Value)
+	assert.Equal(t, Wielded, i.Use)
+
+	// Proper names are all nethack shows of artifacts.
+	i = mustParse("e - the blessed rustproof +7 Stormbringer (weapon in hand)")
+	assert.Equal(t, "runesword", i.Class.Name)
+	assert.Equal(t, "Stormbringer", i.Named)
+	assert.Equal(t, 1, i.Stack)
+	assert.True(t, i.Fixed)
+	assert.Equal(t, Charge{Known: true, Recharged: 1, Charges: -1}, mustParse("f - a wand of striking (1:-1)").Charge)
+}
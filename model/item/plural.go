@@ -0,0 +1,97 @@
+package item
+
+import "strings"
+
+// pluralSeps are the words after which nethack leaves a name alone when it
+// makes it plural: "scrolls labeled KIRJE", "pairs of speed boots".
+var pluralSeps = []string{" of ", " labeled ", " called ", " named "}
+
+// irregularPlurals are the plurals that don't follow the rules in plural.
+// They apply to the ends of words too: "quarterstaves".
+var irregularPlurals = map[string]string{
+	"foot":  "feet",
+	"tooth": "teeth",
+	"knife": "knives",
+	"staff": "staves",
+	"leaf":  "leaves",
+}
+
+// splitPlural splits desc into the part nethack makes plural and the rest.
+func splitPlural(desc string) (head, tail string) {
+	head = desc
+	for _, sep := range pluralSeps {
+		if k := strings.Index(desc, sep); k >= 0 && k < len(head) {
+			head, tail = desc[:k], desc[k:]
+		}
+	}
+	return head, tail
+}
+
+// plural returns the plural of an item's description, the way nethack's
+// makeplural does: "potions of healing", "worthless pieces of red glass",
+// "rubies".
+func plural(desc string) string {
+	head, tail := splitPlural(desc)
+	k := strings.LastIndex(head, " ") + 1
+	word := head[k:]
+	for s, p := range irregularPlurals {
+		if strings.HasSuffix(word, s) {
+			return head[:k] + strings.TrimSuffix(word, s) + p + tail
+		}
+	}
+	switch {
+	case strings.HasSuffix(word, "y") && len(word) > 1 && !strings.ContainsRune("aeiou", rune(word[len(word)-2])):
+		word = word[:len(word)-1] + "ies"
+	case strings.HasSuffix(word, "s"), strings.HasSuffix(word, "x"), strings.HasSuffix(word, "z"),
+		strings.HasSuffix(word, "ch"), strings.HasSuffix(word, "sh"):
+		word += "es"
+	default:
+		word += "s"
+	}
+	return head[:k] + word + tail
+}
+
+// singulars returns the words that word might be the plural of.
+func singulars(word string) []string {
+	var ws []string
+	for s, p := range irregularPlurals {
+		if strings.HasSuffix(word, p) {
+			ws = append(ws, strings.TrimSuffix(word, p)+s)
+		}
+	}
+	if strings.HasSuffix(word, "ies") {
+		ws = append(ws, strings.TrimSuffix(word, "ies")+"y")
+	}
+	if strings.HasSuffix(word, "es") {
+		ws = append(ws, strings.TrimSuffix(word, "es"))
+	}
+	if strings.HasSuffix(word, "s") {
+		ws = append(ws, strings.TrimSuffix(word, "s"))
+	}
+	return ws
+}
+
+// singular returns the singular of desc, the plural description of a stack
+// of items. Since a plural can't always be undone by rule ("boxes" isn't
+// the plural of "boxe"), it's the singular that r knows as an item. If
+// there's none, desc is returned as it is.
+func (r *Registry) singular(desc string) string {
+	if r.known(desc) {
+		return desc
+	}
+	head, tail := splitPlural(desc)
+	k := strings.LastIndex(head, " ") + 1
+	for _, w := range singulars(head[k:]) {
+		if s := head[:k] + w + tail; r.known(s) {
+			return s
+		}
+	}
+	return desc
+}
+
+// known returns whether desc describes an item that r knows of.
+func (r *Registry) known(desc string) bool {
+	desc, _ = water(desc, BUCUnknown)
+	desc, _ = splitSpecies(desc)
+	return r.classFor(desc, "") != nil
+}
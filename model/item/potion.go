@@ -0,0 +1,55 @@
+package item
+
+import "io"
+
+func init() {
+	// Potions all weigh 20 and are made of glass. Water is always clear; the
+	// other potions' appearances are shuffled.
+	data := `name,price,weight,probability,appearance
+gain ability,300,20,42,
+restore ability,100,20,62,
+confusion,100,20,42,
+blindness,150,20,40,
+paralysis,300,20,42,
+speed,200,20,42,
+levitation,200,20,42,
+hallucination,100,20,40,
+invisibility,150,20,40,
+see invisible,50,20,42,
+healing,100,20,57,
+extra healing,100,20,47,
+gain level,300,20,20,
+enlightenment,200,20,20,
+monster detection,150,20,40,
+object detection,150,20,42,
+gain energy,150,20,42,
+sleeping,100,20,42,
+full healing,200,20,10,
+polymorph,200,20,10,
+booze,50,20,42,
+sickness,50,20,42,
+fruit juice,50,20,42,
+acid,250,20,10,
+oil,250,20,30,
+water,100,20,92,clear potion`
+	csv := mustMapCsv(data)
+
+	for csv.next() {
+		c := &Class{
+			Category:    Potion,
+			Name:        "potion of " + csv.get("name"),
+			Price:       mustInt(csv.get("price")),
+			Weight:      mustInt(csv.get("weight")),
+			Probability: mustProb(csv.get("probability")),
+			Material:    Glass,
+			Appearance:  csv.get("appearance"),
+		}
+		classes[c.Name] = c
+		if c.Appearance == "" {
+			potionShuffle.names = append(potionShuffle.names, c.Name)
+		}
+	}
+	if csv.err != io.EOF {
+		panic(csv.err)
+	}
+}
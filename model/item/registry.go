@@ -1,10 +1,287 @@
 package item
 
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
 // Registry is a per-game object that maps item appearances to true item class.
-// It also hosts
+// It also hosts this game's copy of every Class, so that what we learn about
+// classes in one game doesn't leak into another.
+//
+// The appearances of some classes are shuffled at the start of each game: a
+// "platinum wand" is a wand of digging in one game and a wand of wishing in
+// the next. Until we identify it, every platinum wand shares a single *Class
+// with a blank Name. Identifying the appearance fills in that Class, so every
+// Item with that appearance learns what it is at once.
 type Registry struct {
+	// byName holds this game's copy of each class, under its name and its
+	// alternate name, if any. Once an appearance is identified, the class's
+	// names point to the appearance's shared Class.
+	byName map[string]*Class
+
+	// byAppearance holds the Class shared by all items with each appearance.
+	byAppearance map[string]*Class
+
+	// candidates are the classes each shuffled appearance might be.
+	candidates map[string][]*Class
+
+	// groups are the shuffled groups, by each of their appearances.
+	groups map[string]*group
+
+	// onIdentify are called whenever an appearance is identified.
+	onIdentify []func(appearance string, c *Class)
 }
 
+// NewRegistry returns a Registry for a new game, in which we know nothing
+// about which appearance is which.
+func NewRegistry() *Registry {
+	r := &Registry{
+		byName:       make(map[string]*Class),
+		byAppearance: make(map[string]*Class),
+		candidates:   make(map[string][]*Class),
+		groups:       make(map[string]*group),
+	}
+
+	// Several names may point to the same class, and the copies should too.
+	copies := make(map[*Class]*Class)
+	for name, c := range classes {
+		cp, ok := copies[c]
+		if !ok {
+			v := *c
+			cp = &v
+			copies[c] = cp
+		}
+		r.byName[name] = cp
+	}
+
+	// Appearances that aren't shuffled tell us what an item is right away,
+	// unless another class has that appearance as its name. (The cheap plastic
+	// imitation looks like the Amulet of Yendor.) Several classes may have the
+	// same fixed appearance, as the dunce cap and cornuthaum are both "conical
+	// hat"s. Those are grouped like gems.
+	fixed := make(map[string][]*Class)
+	for _, c := range copies {
+		if _, isName := r.byName[c.Appearance]; c.Appearance != "" && !isName {
+			fixed[c.Appearance] = append(fixed[c.Appearance], c)
+		}
+	}
+	groups := shuffles
+	for a, cs := range fixed {
+		if len(cs) == 1 {
+			r.byAppearance[a] = cs[0]
+			continue
+		}
+		s := &shuffle{Category: cs[0].Category, appearances: []string{a}, shared: map[string][]string{a: nil}}
+		for _, c := range cs {
+			s.shared[a] = append(s.shared[a], c.Name)
+		}
+		sort.Strings(s.shared[a])
+		groups = append(groups, s)
+	}
+
+	for _, s := range groups {
+		g := &group{shuffle: s, used: make(map[string]bool)}
+		for _, a := range s.appearances {
+			names := s.names
+			if s.shared != nil {
+				names = s.shared[a]
+			}
+			var cands []*Class
+			for _, n := range names {
+				cands = append(cands, r.byName[n])
+			}
+			c := &Class{Category: s.Category, Appearance: a}
+			share(c, cands)
+			r.byAppearance[a] = c
+			r.candidates[a] = cands
+			r.groups[a] = g
+		}
+	}
+	return r
+}
+
+// ByAppearance returns the Class shared by all items with an appearance, or
+// nil if there's no such appearance.
 func (r *Registry) ByAppearance(appearance string) *Class {
+	return r.byAppearance[appearance]
+}
+
+// ByName returns this game's Class with a name, or nil if there's no such
+// class. If the class's appearance is shuffled and hasn't been identified,
+// the result isn't shared with any Item.
+func (r *Registry) ByName(name string) *Class {
+	return r.byName[name]
+}
+
+// ByCalled returns the Class of category cat we've called a name, or nil if
+// we haven't called anything of that category that. Nethack lets us call a
+// potion and a scroll the same thing, or even two potions; if we have, the
+// one whose appearance sorts first is returned.
+func (r *Registry) ByCalled(cat Category, called string) *Class {
+	return r.byCalled(Class{Category: cat}, called)
+}
+
+// byCalled is like ByCalled, for items described as the generic class g:
+// "boots called speedy" aren't the cloak we called speedy.
+func (r *Registry) byCalled(g Class, called string) *Class {
+	var found *Class
+	for _, c := range r.byAppearance {
+		if c.Called != called || c.Category != g.Category {
+			continue
+		}
+		if len(g.Slots) > 0 && (len(c.Slots) == 0 || c.Slots[0] != g.Slots[0]) {
+			continue
+		}
+		if found == nil || c.Appearance < found.Appearance {
+			found = c
+		}
+	}
+	return found
+}
+
+// Candidates returns the classes that items with an appearance might be. For
+// an appearance that isn't shuffled, or has been identified, there's just
+// the one.
+func (r *Registry) Candidates(appearance string) []*Class {
+	if cands, ok := r.candidates[appearance]; ok {
+		return cands
+	}
+	if c := r.byAppearance[appearance]; c != nil {
+		return []*Class{c}
+	}
 	return nil
 }
+
+// Call records what we've called the items with an appearance, as nethack's
+// #name command does. An empty name removes it.
+func (r *Registry) Call(appearance, name string) error {
+	c := r.byAppearance[appearance]
+	if c == nil {
+		return fmt.Errorf("no such appearance: %q", appearance)
+	}
+	c.Called = name
+	return nil
+}
+
+// Parse is like the package's Parse, but the Item's Class comes from the
+// Registry. Items with the same appearance, or the same name, share a Class.
+func (r *Registry) Parse(s string) (*Item, error) {
+	return parse(s, r, 0)
+}
+
+// ParseIn is like the package's ParseIn, but the Item's Class comes from the
+// Registry.
+func (r *Registry) ParseIn(s string, cat Category) (*Item, error) {
+	return parse(s, r, cat)
+}
+
+// classFor returns the Class for an item described as desc, and called
+// called if that's not empty. If r is nil, or doesn't know the class, a new
+// Class is returned with as much filled in as we can tell from desc. If
+// desc isn't an item we know of at all, classFor returns nil.
+func (r *Registry) classFor(desc, called string) *Class {
+	// Nethack shows boots and gloves as "pair of ...", whether or not
+	// they're identified.
+	desc = strings.TrimPrefix(desc, "pair of ")
+
+	if r != nil {
+		// Nethack shows the items we've called something by their generic
+		// name: "potion called oil".
+		if g, ok := genericNames[desc]; ok && called != "" {
+			if c := r.byCalled(g, called); c != nil {
+				return c
+			}
+		}
+		if c := r.byAppearance[desc]; c != nil {
+			r.inUse(desc)
+			return c
+		}
+		if c := r.byName[desc]; c != nil {
+			return c
+		}
+	}
+
+	if g, ok := genericNames[desc]; ok {
+		g.Called = called
+		return &g
+	}
+	for _, s := range shuffles {
+		for _, a := range s.appearances {
+			if a != desc {
+				continue
+			}
+			names := s.names
+			if s.shared != nil {
+				names = s.shared[a]
+			}
+			var cands []*Class
+			for _, n := range names {
+				cands = append(cands, classes[n])
+			}
+			c := &Class{Category: s.Category, Appearance: a, Called: called}
+			share(c, cands)
+			return c
+		}
+	}
+	if c, ok := classes[desc]; ok {
+		v := *c
+		v.Called = called
+		return &v
+	}
+
+	// Appearances that aren't shuffled, like "runed dagger". Several
+	// classes may share one, like the "bag"s, and then we can only say what
+	// they have in common.
+	var cands []*Class
+	for _, c := range classes {
+		if c.Appearance == desc && !containsClass(cands, c) {
+			cands = append(cands, c)
+		}
+	}
+	switch len(cands) {
+	case 0:
+		return nil
+	case 1:
+		v := *cands[0]
+		v.Called = called
+		return &v
+	}
+	c := &Class{Category: cands[0].Category, Appearance: desc, Called: called}
+	share(c, cands)
+	return c
+}
+
+// containsClass returns whether c is in cs. A class may be in the classes
+// map under more than one name.
+func containsClass(cs []*Class, c *Class) bool {
+	for _, x := range cs {
+		if x == c {
+			return true
+		}
+	}
+	return false
+}
+
+// share fills in what c, the Class of an appearance that hasn't been
+// identified, has in common with all of its candidates: where it's worn, and
+// the base AC and MC of armor. Every pair of random boots gives 1 AC, for
+// example, whichever boots they turn out to be. What the candidates don't
+// agree on is left at its zero value.
+func share(c *Class, cands []*Class) {
+	if len(cands) == 0 {
+		return
+	}
+	// Whatever it turns out to be, it's worn in the same place.
+	c.Slots = cands[0].Slots
+	c.AC, c.MagicCancellation = cands[0].AC, cands[0].MagicCancellation
+	for _, o := range cands[1:] {
+		if o.AC != c.AC {
+			c.AC = 0
+		}
+		if o.MagicCancellation != c.MagicCancellation {
+			c.MagicCancellation = 0
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package item
+
+import (
+	"testing"
+
+	"github.com/jaguilar/nh/model/anatomy"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistryAppearances(t *testing.T) {
+	r := NewRegistry()
+	for _, s := range shuffles {
+		if s.shared == nil {
+			assert.True(t, len(s.appearances) >= len(s.names), "%v: %v", s.Category, s.appearances)
+		}
+		for _, a := range s.appearances {
+			want := len(s.names)
+			if s.shared != nil {
+				want = len(s.shared[a])
+			}
+			if assert.NotNil(t, r.ByAppearance(a), a) {
+				assert.Equal(t, s.Category, r.ByAppearance(a).Category)
+				assert.Len(t, r.Candidates(a), want, a)
+			}
+		}
+	}
+	assert.Len(t, r.Candidates("wooden ring"), 28)
+	assert.Len(t, r.Candidates("circular amulet"), 9)
+	assert.Len(t, r.Candidates("tattered cape"), 4)
+	assert.Len(t, r.Candidates("combat boots"), 7)
+	assert.Len(t, r.Candidates("glass wand"), 24)
+	assert.Len(t, r.Candidates("ruby potion"), 25)
+	assert.Len(t, r.Candidates("scroll labeled NR 9"), 21)
+	assert.Len(t, r.Candidates("parchment spellbook"), 40)
+	assert.Len(t, r.Candidates("white gem"), 4)
+	assert.Len(t, r.Candidates("gray stone"), 4)
+	assert.Len(t, r.Candidates("bag"), 4)
+
+	// Appearances that aren't shuffled are known from the start.
+	assert.Equal(t, "elven dagger", r.ByAppearance("runed dagger").Name)
+	assert.Equal(t, "orcish ring mail", r.ByAppearance("crude ring mail").Name)
+	assert.Nil(t, r.ByAppearance("Amulet of Yendor"))
+	assert.Equal(t, "potion of water", r.ByAppearance("clear potion").Name)
+
+	// Fixed appearances that several classes share have to be identified.
+	hat := r.ByAppearance("conical hat")
+	if assert.NotNil(t, hat) {
+		assert.Equal(t, "", hat.Name)
+		assert.Equal(t, []string{"cornuthaum", "dunce cap"}, classNames(r.Candidates("conical hat")))
+	}
+	assert.Equal(t, []anatomy.BodyPart{anatomy.Head}, r.ByAppearance("plumed helmet").Slots)
+
+	// Unidentified armor has the AC and MC its candidates share.
+	assert.Equal(t, 1, r.ByAppearance("combat boots").AC)
+	assert.Equal(t, 1, r.ByAppearance("old gloves").AC)
+	assert.Equal(t, 1, r.ByAppearance("crested helmet").AC)
+	cape := r.ByAppearance("tattered cape")
+	assert.Equal(t, 0, cape.AC)
+	assert.Equal(t, 0, cape.MagicCancellation)
+	assert.Nil(t, r.Exclude("tattered cape", "cloak of protection"))
+	assert.Equal(t, 1, cape.AC)
+	assert.Equal(t, 0, cape.MagicCancellation)
+	assert.Nil(t, r.Exclude("tattered cape", "cloak of magic resistance"))
+	assert.Equal(t, 2, cape.MagicCancellation)
+}
+
+func classNames(cs []*Class) []string {
+	var ns []string
+	for _, c := range cs {
+		ns = append(ns, c.Name)
+	}
+	return ns
+}
+
+func TestRegistrySharesClasses(t *testing.T) {
+	r := NewRegistry()
+	a, err := r.Parse("a - a platinum wand")
+	assert.Nil(t, err)
+	b, err := r.Parse("b - an uncursed platinum wand (0:4)")
+	assert.Nil(t, err)
+	if assert.True(t, a.Class == b.Class) {
+		assert.Equal(t, Wand, a.Class.Category)
+		assert.Equal(t, "platinum wand", a.Class.Appearance)
+		assert.Equal(t, "", a.Class.Name)
+	}
+
+	assert.Nil(t, r.Call("murky potion", "oil"))
+	c, err := r.Parse("c - 2 potions called oil")
+	assert.Nil(t, err)
+	assert.True(t, c.Class == r.ByAppearance("murky potion"))
+
+	// The same name may be called on items of different categories.
+	assert.Nil(t, r.Call("scroll labeled NR 9", "oil"))
+	assert.Nil(t, r.Call("tattered cape", "oil"))
+	scroll, err := r.Parse("c - a scroll called oil")
+	assert.Nil(t, err)
+	assert.True(t, scroll.Class == r.ByAppearance("scroll labeled NR 9"))
+	cloak, err := r.Parse("c - a cloak called oil")
+	assert.Nil(t, err)
+	assert.True(t, cloak.Class == r.ByAppearance("tattered cape"))
+	assert.True(t, r.ByCalled(Potion, "oil") == r.ByAppearance("murky potion"))
+	assert.Nil(t, r.ByCalled(Wand, "oil"))
+	wand, err := r.Parse("c - a wand called oil")
+	assert.Nil(t, err)
+	assert.Equal(t, Wand, wand.Class.Category)
+	assert.Equal(t, "oil", wand.Class.Called)
+
+	boots, err := r.Parse("d - a pair of combat boots (being worn)")
+	assert.Nil(t, err)
+	assert.True(t, boots.Class == r.ByAppearance("combat boots"))
+
+	// What one item tells us about its material is kept to itself.
+	oak, err := r.Parse("e - a fireproof oak wand")
+	assert.Nil(t, err)
+	assert.Equal(t, Wood, oak.Material)
+	assert.Equal(t, Material(""), r.ByAppearance("oak wand").Material)
+}
+
+func TestRegistryIdentify(t *testing.T) {
+	r := NewRegistry()
+	ring, _ := r.Parse("a - a wooden ring")
+	assert.Nil(t, r.Call("wooden ring", "meh"))
+
+	assert.NotNil(t, r.Identify("wooden ring", "meat ring"))
+	assert.NotNil(t, r.Identify("pewter ring", "ring of adornment"))
+	assert.Nil(t, r.Identify("wooden ring", "ring of free action"))
+
+	assert.Equal(t, "ring of free action", ring.Class.Name)
+	assert.Equal(t, "wooden ring", ring.Class.Appearance)
+	assert.Equal(t, "meh", ring.Class.Called)
+	assert.Equal(t, 200, ring.Class.Price)
+	assert.True(t, r.ByName("ring of free action") == ring.Class)
+	assert.Equal(t, []*Class{ring.Class}, r.Candidates("wooden ring"))
+
+	named, _ := r.Parse("b - an uncursed ring of free action")
+	assert.True(t, named.Class == ring.Class)
+
+	// Each game has its own registry.
+	assert.Equal(t, "", NewRegistry().ByAppearance("wooden ring").Name)
+	assert.Equal(t, "ring of free action", classes["ring of free action"].Name)
+	assert.Equal(t, "", classes["ring of free action"].Appearance)
+}
+
+func TestParseWithoutRegistry(t *testing.T) {
+	a := mustParse("a - a platinum wand")
+	b := mustParse("b - a platinum wand")
+	assert.False(t, a.Class == b.Class)
+	assert.Equal(t, Wand, a.Class.Category)
+	assert.Equal(t, "platinum wand", a.Class.Appearance)
+
+	assert.Equal(t, Scroll, mustParse("c - a scroll labeled NR 9").Class.Category)
+	assert.Equal(t, Armor, mustParse("d - an uncursed +0 leather armor").Class.Category)
+	assert.Equal(t, Potion, mustParse("e - a potion called sickness").Class.Category)
+
+	// Fixed appearances, whether or not several tools share them.
+	assert.Equal(t, "elven dagger", mustParse("f - a runed dagger").Class.Name)
+	bag := mustParse("g - a bag")
+	assert.Equal(t, Tool, bag.Class.Category)
+	assert.Equal(t, "", bag.Class.Name)
+	assert.Equal(t, "g - a bag", bag.String())
+	candles := mustParse("h - 2 candles")
+	assert.Equal(t, Tool, candles.Class.Category)
+	assert.Equal(t, 2, candles.Stack)
+	assert.Equal(t, "h - 2 candles", candles.String())
+	for _, s := range []string{"lamp", "whistle", "flute", "horn", "harp", "drum"} {
+		i, err := Parse("i - a " + s)
+		if assert.Nil(t, err, s) {
+			assert.Equal(t, s, i.Class.Appearance)
+		}
+	}
+}
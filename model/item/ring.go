@@ -40,11 +40,19 @@ teleport control,300,3,1,`
 	csv := mustMapCsv(data)
 
 	for csv.next() {
+		// All rings but the meat ring are shown as "ring of ..." once
+		// identified, and have shuffled appearances.
+		name := csv.get("name")
+		if name != "meat ring" {
+			name = "ring of " + name
+			ringShuffle.names = append(ringShuffle.names, name)
+		}
 		c := &Class{
-			Category: Ring,
-			Name:     csv.get("name"),
-			Price:    mustInt(csv.get("price")),
-			Weight:   mustInt(csv.get("weight")),
+			Category:    Ring,
+			Name:        name,
+			Price:       mustInt(csv.get("price")),
+			Weight:      mustInt(csv.get("weight")),
+			Probability: mustProb(csv.get("probability")),
 		}
 		classes[c.Name] = c
 		if csv.get("charge") != "" {
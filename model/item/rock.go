@@ -0,0 +1,14 @@
+package item
+
+func init() {
+	// A statue weighs as much as the monster it depicts. We use the weight
+	// of a human-sized one.
+	classes["boulder"] = &Class{Category: Boulder, Name: "boulder", Weight: 6000, Probability: 100, Material: Mineral}
+	classes["statue"] = &Class{Category: Statue, Name: "statue", Weight: 2500, Probability: 900, Material: Mineral}
+
+	// The ball and chain a prisoner is punished with, and gold, are each
+	// alone in their categories too.
+	classes["heavy iron ball"] = &Class{Category: HeavyIronBall, Name: "heavy iron ball", Price: 10, Weight: 480, Material: Iron}
+	classes["iron chain"] = &Class{Category: IronChain, Name: "iron chain", Weight: 120, Material: Iron}
+	classes["gold piece"] = &Class{Category: Coins, Name: "gold piece", Price: 1, Weight: 1, Material: Gold}
+}
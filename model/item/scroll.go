@@ -0,0 +1,52 @@
+package item
+
+import "io"
+
+func init() {
+	// Scrolls all weigh 5 and are made of paper. Blank paper and mail have
+	// fixed appearances; the others' labels are shuffled.
+	data := `name,price,weight,probability,appearance
+enchant armor,80,5,63,
+destroy armor,100,5,32,
+confuse monster,100,5,53,
+scare monster,100,5,35,
+remove curse,80,5,65,
+enchant weapon,60,5,80,
+create monster,200,5,45,
+taming,200,5,15,
+genocide,300,5,18,
+light,50,5,90,
+teleportation,100,5,55,
+gold detection,100,5,33,
+food detection,100,5,25,
+identify,20,5,180,
+magic mapping,100,5,45,
+amnesia,200,5,35,
+fire,100,5,30,
+earth,200,5,20,
+punishment,300,5,15,
+charging,300,5,15,
+stinking cloud,300,5,15,
+blank paper,60,5,28,unlabeled scroll
+mail,0,5,0,stamped scroll`
+	csv := mustMapCsv(data)
+
+	for csv.next() {
+		c := &Class{
+			Category:    Scroll,
+			Name:        "scroll of " + csv.get("name"),
+			Price:       mustInt(csv.get("price")),
+			Weight:      mustInt(csv.get("weight")),
+			Probability: mustProb(csv.get("probability")),
+			Material:    Paper,
+			Appearance:  csv.get("appearance"),
+		}
+		classes[c.Name] = c
+		if c.Appearance == "" {
+			scrollShuffle.names = append(scrollShuffle.names, c.Name)
+		}
+	}
+	if csv.err != io.EOF {
+		panic(csv.err)
+	}
+}
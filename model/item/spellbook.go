@@ -0,0 +1,82 @@
+package item
+
+import "io"
+
+func init() {
+	// Spellbooks all weigh 50 and are made of paper, and cost 100 for each
+	// level of their spell. Blank paper and the Book of the Dead have fixed
+	// appearances; the others' are shuffled.
+	data := `name,level,school,probability,appearance
+dig,5,matter,20,
+magic missile,2,attack,45,
+fireball,4,attack,20,
+cone of cold,4,attack,10,
+sleep,1,enchantment,50,
+finger of death,7,attack,5,
+light,1,divination,45,
+detect monsters,1,divination,43,
+healing,1,healing,40,
+knock,1,matter,35,
+force bolt,1,attack,35,
+confuse monster,2,enchantment,30,
+cure blindness,2,healing,25,
+drain life,2,attack,10,
+slow monster,2,enchantment,30,
+wizard lock,2,matter,30,
+create monster,2,clerical,35,
+detect food,2,divination,30,
+cause fear,3,enchantment,25,
+clairvoyance,3,divination,15,
+cure sickness,3,healing,32,
+charm monster,3,enchantment,20,
+haste self,3,escape,33,
+detect unseen,3,divination,20,
+levitation,4,escape,20,
+extra healing,3,healing,27,
+restore ability,4,healing,25,
+invisibility,4,escape,25,
+detect treasure,4,divination,20,
+remove curse,3,clerical,25,
+magic mapping,5,divination,18,
+identify,3,divination,20,
+turn undead,6,clerical,16,
+polymorph,6,matter,10,
+teleport away,6,escape,15,
+create familiar,6,clerical,10,
+cancellation,7,matter,15,
+protection,1,clerical,18,
+jumping,1,escape,20,
+stone to flesh,3,healing,15,
+blank paper,0,,18,plain spellbook
+Book of the Dead,0,,0,papyrus spellbook`
+	csv := mustMapCsv(data)
+
+	for csv.next() {
+		name := csv.get("name")
+		if name != "Book of the Dead" {
+			name = "spellbook of " + name
+		}
+		level := mustInt(csv.get("level"))
+		c := &Class{
+			Category:    Spellbook,
+			Name:        name,
+			Price:       100 * level,
+			Weight:      50,
+			Probability: mustProb(csv.get("probability")),
+			Material:    Paper,
+			Appearance:  csv.get("appearance"),
+			SpellLevel:  level,
+			School:      School(csv.get("school")),
+		}
+		if name == "Book of the Dead" {
+			c.Price, c.Weight, c.Unique = 10000, 20, true
+		}
+		classes[c.Name] = c
+		if c.Appearance == "" {
+			spellbookShuffle.names = append(spellbookShuffle.names, c.Name)
+		}
+	}
+	if csv.err != io.EOF {
+		panic(csv.err)
+	}
+}
@@ -0,0 +1,49 @@
+package item
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTables(t *testing.T) {
+	count := make(map[Category]int)
+	for n, c := range classes {
+		if n != c.Name {
+			continue // An alternate name.
+		}
+		count[c.Category]++
+		switch c.Category {
+		case Wand:
+			assert.Equal(t, 7, c.Weight, n)
+		case Spellbook:
+			if c.Appearance == "" {
+				assert.Equal(t, 100*c.SpellLevel, c.Price, n)
+				assert.NotEqual(t, School(""), c.School, n)
+			}
+		case Comestible:
+			assert.True(t, c.Edible, n)
+		case Scroll:
+			// Price identification relies on each scroll being in one
+			// of nethack's price groups.
+			if c.Name != "scroll of mail" {
+				assert.Contains(t, []int{20, 50, 60, 80, 100, 200, 300}, c.Price, n)
+			}
+		}
+	}
+	for cat, want := range map[Category]int{
+		Wand: 24, Potion: 26, Scroll: 23, Spellbook: 42, Tool: 47, Gem: 36, Comestible: 28,
+		Boulder: 1, Statue: 1,
+	} {
+		assert.Equal(t, want, count[cat], cat.String())
+	}
+
+	assert.Equal(t, Ray, classes["wand of death"].Zap)
+	assert.Equal(t, Immediate, classes["wand of striking"].Zap)
+	assert.Equal(t, NoDir, classes["wand of wishing"].Zap)
+	assert.Equal(t, Enchantment, classes["spellbook of sleep"].School)
+	assert.Equal(t, 800, classes["food ration"].Nutrition)
+	assert.Equal(t, 175, classes["amulet of ESP"].Probability)
+	assert.Equal(t, 100, classes["scroll of food detection"].Price)
+	assert.Equal(t, 25, classes["scroll of food detection"].Probability)
+}
@@ -0,0 +1,123 @@
+package item
+
+import (
+	"io"
+	"strings"
+)
+
+func init() {
+	// Tools that share an appearance, like the bags and horns, can only be
+	// told apart once identified. The weapon-tools (pick-axe, grappling
+	// hook and unicorn horn) are in the weapon table.
+	data := `name,price,weight,probability,material,appearance,props
+large box,8,350,40,wood,,container|lockable
+chest,16,600,35,wood,,container|lockable
+ice box,42,900,5,plastic,,container
+sack,2,15,35,cloth,bag,container
+oilskin sack,100,15,5,cloth,bag,container
+bag of holding,100,15,20,cloth,bag,container
+bag of tricks,100,15,20,cloth,bag,charged
+skeleton key,10,3,80,iron,key,unlocks
+lock pick,20,4,60,iron,,unlocks
+credit card,10,1,15,plastic,,unlocks
+tallow candle,10,2,20,wax,candle,light
+wax candle,20,2,5,wax,candle,light
+brass lantern,12,30,30,copper,,light
+oil lamp,10,20,45,copper,lamp,light
+magic lamp,500,20,15,copper,lamp,light
+expensive camera,200,12,15,plastic,,charged
+mirror,10,13,45,glass,looking glass,
+crystal ball,60,150,15,glass,glass orb,charged
+lenses,80,3,5,glass,,
+blindfold,20,2,50,cloth,,
+towel,50,2,50,cloth,,
+saddle,150,200,5,leather,,
+leash,20,12,65,leather,,
+stethoscope,75,2,25,iron,,
+tinning kit,30,100,15,iron,,charged
+tin opener,30,4,35,iron,,
+can of grease,20,15,15,iron,,charged
+figurine,80,50,25,mineral,,
+magic marker,50,2,15,plastic,,charged
+land mine,180,300,0,iron,,
+beartrap,60,200,0,iron,,
+tin whistle,10,3,100,metal,whistle,
+magic whistle,10,3,30,metal,whistle,
+wooden flute,12,5,4,wood,flute,
+magic flute,36,5,2,wood,flute,charged
+tooled horn,15,18,5,bone,horn,
+frost horn,50,18,2,bone,horn,charged
+fire horn,50,18,2,bone,horn,charged
+horn of plenty,50,18,2,bone,horn,charged
+wooden harp,50,30,4,wood,harp,
+magic harp,50,30,2,wood,harp,charged
+bell,50,30,2,copper,,
+bugle,15,10,4,copper,,
+leather drum,25,25,4,leather,drum,
+drum of earthquake,25,25,2,leather,drum,charged
+Candelabrum of Invocation,5000,10,0,gold,candelabrum,light|unique
+Bell of Opening,5000,10,0,silver,silver bell,charged|unique`
+	csv := mustMapCsv(data)
+
+	for csv.next() {
+		c := &Class{
+			Category:    Tool,
+			Name:        csv.get("name"),
+			Price:       mustInt(csv.get("price")),
+			Weight:      mustInt(csv.get("weight")),
+			Probability: mustProb(csv.get("probability")),
+			Material:    Material(csv.get("material")),
+			Appearance:  csv.get("appearance"),
+		}
+		for _, p := range strings.Split(csv.get("props"), "|") {
+			switch p {
+			case "container":
+				c.Container = true
+			case "lockable":
+				c.Lockable = true
+			case "light":
+				c.LightSource = true
+			case "charged":
+				c.Charged = true
+			case "unlocks":
+				c.Unlocks = true
+			case "unique":
+				c.Unique = true
+			case "":
+			default:
+				panic("unknown tool property: " + p)
+			}
+		}
+		classes[c.Name] = c
+	}
+	if csv.err != io.EOF {
+		panic(csv.err)
+	}
+}
+
+// UnlockChance returns the percent chance that each turn spent trying to
+// open a lock with a tool of class c succeeds, for a character with
+// dexterity dex. box is set if the lock is on a box rather than a door.
+// Rogues are better with lock picks and credit cards. It returns 0 if c
+// can't open locks.
+func UnlockChance(c *Class, box bool, dex int, rogue bool) int {
+	var r int
+	if rogue {
+		r = 1
+	}
+	switch {
+	case c.Name == "skeleton key" && box:
+		return 75 + dex
+	case c.Name == "skeleton key":
+		return 70 + dex
+	case c.Name == "lock pick" && box:
+		return 4*dex + 25*r
+	case c.Name == "lock pick":
+		return 3*dex + 30*r
+	case c.Name == "credit card" && box:
+		return dex + 20*r
+	case c.Name == "credit card":
+		return 2*dex + 20*r
+	}
+	return 0
+}
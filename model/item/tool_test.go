@@ -0,0 +1,33 @@
+package item
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToolProperties(t *testing.T) {
+	assert.True(t, classes["bag of holding"].Container)
+	assert.False(t, classes["bag of holding"].Lockable)
+	assert.True(t, classes["chest"].Lockable)
+	assert.False(t, classes["bag of tricks"].Container)
+	assert.True(t, classes["bag of tricks"].Charged)
+	assert.True(t, classes["brass lantern"].LightSource)
+	assert.True(t, classes["wand of striking"].Charged)
+	assert.True(t, classes["lock pick"].Unlocks)
+}
+
+func TestUnlockChance(t *testing.T) {
+	assert.Equal(t, 86, UnlockChance(classes["skeleton key"], false, 16, false))
+	assert.Equal(t, 48, UnlockChance(classes["lock pick"], false, 16, false))
+	assert.Equal(t, 78, UnlockChance(classes["lock pick"], false, 16, true))
+	assert.Equal(t, 52, UnlockChance(classes["credit card"], false, 16, true))
+	assert.Equal(t, 0, UnlockChance(classes["towel"], false, 16, true))
+
+	assert.Equal(t, 91, UnlockChance(classes["skeleton key"], true, 16, false))
+	assert.Equal(t, 64, UnlockChance(classes["lock pick"], true, 16, false))
+	assert.Equal(t, 89, UnlockChance(classes["lock pick"], true, 16, true))
+	assert.Equal(t, 16, UnlockChance(classes["credit card"], true, 16, false))
+	assert.Equal(t, 36, UnlockChance(classes["credit card"], true, 16, true))
+	assert.Equal(t, 0, UnlockChance(classes["towel"], true, 16, true))
+}
@@ -0,0 +1,54 @@
+package item
+
+import "io"
+
+func init() {
+	// Wands all weigh 7. Every wand's appearance is shuffled.
+	data := `name,price,weight,probability,zap
+light,100,7,95,nodir
+secret door detection,150,7,50,nodir
+enlightenment,150,7,15,nodir
+create monster,200,7,50,nodir
+wishing,500,7,5,nodir
+nothing,100,7,25,immediate
+striking,150,7,75,immediate
+make invisible,150,7,45,immediate
+slow monster,150,7,50,immediate
+speed monster,150,7,50,immediate
+undead turning,150,7,50,immediate
+polymorph,200,7,45,immediate
+cancellation,200,7,45,immediate
+teleportation,200,7,45,immediate
+opening,150,7,25,immediate
+locking,150,7,25,immediate
+probing,150,7,30,immediate
+digging,150,7,55,ray
+magic missile,150,7,50,ray
+fire,175,7,40,ray
+cold,175,7,40,ray
+sleep,175,7,50,ray
+death,500,7,5,ray
+lightning,175,7,40,ray`
+	csv := mustMapCsv(data)
+
+	for csv.next() {
+		zap, ok := zaps[csv.get("zap")]
+		if !ok {
+			panic("unknown zap type: " + csv.get("zap"))
+		}
+		c := &Class{
+			Category:    Wand,
+			Name:        "wand of " + csv.get("name"),
+			Price:       mustInt(csv.get("price")),
+			Weight:      mustInt(csv.get("weight")),
+			Probability: mustProb(csv.get("probability")),
+			Zap:         zap,
+			Charged:     true,
+		}
+		classes[c.Name] = c
+		wandShuffle.names = append(wandShuffle.names, c.Name)
+	}
+	if csv.err != io.EOF {
+		panic(csv.err)
+	}
+}
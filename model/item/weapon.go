@@ -124,8 +124,10 @@ unicorn horn,100,20,0,BONE,,1,d12,d12,`
 	}
 }
 
-var altNameRe = regexp.MustCompile(`^(.*?)\W*(\(.*\))?$`)
+var altNameRe = regexp.MustCompile(`^(.*?)\W*(?:\((.*)\))?$`)
 
+// parseAltName splits a name like "short sword (wakizashi)" into the name and
+// the alternate name Samurai know the item by.
 func parseAltName(orig string) (string, string) {
 	matches := altNameRe.FindStringSubmatch(orig)
 	if matches == nil {
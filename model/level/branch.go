@@ -7,6 +7,7 @@ const (
 	Mines             = "m"
 	Sokoban           = "s"
 	Ghennom           = "g"
+	Quest             = "q"
 	Ludios            = "l"
 	VladsTower        = "v"
 	Planes            = "p"
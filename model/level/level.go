@@ -9,12 +9,21 @@ var (
 	SuspectedMonsterLimit = 30
 )
 
+// The dimensions of a Level's Map. These are nethack's ROWNO and COLNO.
+// Column 0 is never part of the level, but we keep it so that the Map can
+// be indexed with screen columns.
+const (
+	Height = 21
+	Width  = 80
+)
+
 type Level struct {
 	// LevelID is the unique identifier of the level (branch+floor).
 	LevelID
 
-	// Map is all the Squares in the level.
-	Map [20][78]square.Square
+	// Map is all the Squares in the level, indexed [y][x]. Row 0 is the top
+	// row of the map, which is the second row of the screen.
+	Map [Height][Width]square.Square
 
 	// SuspectedMonsters are the monsters we suspect are on this level, but don't
 	// know for sure. This includes any monster that was seen once and not killed
@@ -22,3 +31,20 @@ type Level struct {
 	// the monsters in the squares.
 	SuspectedMonsters []*mon.Monster
 }
+
+// stairsAt returns whether there's a staircase of kind t (square.StaircaseUp
+// or square.StaircaseDown) at (y, x), and whether we know where any such
+// staircase on the level is.
+func (l *Level) stairsAt(t square.Terrain, y, x int) (here, known bool) {
+	for sy := range l.Map {
+		for sx := range l.Map[sy] {
+			if l.Map[sy][sx].Feature == t {
+				known = true
+				if sy == y && sx == x {
+					here = true
+				}
+			}
+		}
+	}
+	return here, known
+}
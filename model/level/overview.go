@@ -0,0 +1,241 @@
+package level
+
+import "github.com/jaguilar/nh/model/square"
+
+// Via is how the player got from one level to another.
+type Via int
+
+const (
+	// ViaUnknown - we don't know how we got here. This is the case at the
+	// start of the game and after a level teleport.
+	ViaUnknown Via = iota
+	ViaStairsDown
+	ViaStairsUp
+
+	// ViaFall - through a trap door or hole.
+	ViaFall
+	ViaPortal
+)
+
+// Link is a connection from one level to another that we've used.
+type Link struct {
+	To LevelID
+	Via
+}
+
+// Landmarks are what we noticed on arriving at a level that might tell us
+// which branch it's in.
+type Landmarks struct {
+	// Y and X are where we arrived.
+	Y, X int
+
+	// Gnomes is the number of G glyphs in view.
+	Gnomes int
+
+	// Boulders is the number of boulders in view. Sokoban is full of them.
+	Boulders int
+
+	// IrregularWalls is set if the walls in view don't look like the walls of
+	// rectangular rooms. The Gnomish Mines are all irregular caves.
+	IrregularWalls bool
+
+	// Hot is set if we were told "It is hot here." That only happens on
+	// entering the Valley of the Dead.
+	Hot bool
+}
+
+// Arrival is everything we know about a level change.
+type Arrival struct {
+	// Dlvl, Quest and Endgame are as shown on the status line. See
+	// pc.Player.
+	Dlvl           int
+	Quest, Endgame bool
+
+	Via
+	Landmarks
+}
+
+const (
+	// The Mines branch from one of dungeon levels 2 through 4, so the top
+	// level of the Mines is one of 3 through 5.
+	minesTopMin, minesTopMax = 3, 5
+
+	// The Oracle is on one of levels 5 through 9, and the bottom level of
+	// Sokoban has the same Dlvl as the Oracle.
+	oracleMin, oracleMax = 5, 9
+
+	// If we can see this many boulders on arriving somewhere by going up,
+	// we're in Sokoban.
+	sokobanBoulders = 8
+)
+
+// Overview is our overview of the whole dungeon: where we are, and how the
+// levels we've seen connect to each other.
+type Overview struct {
+	// Current is the level the player is on.
+	Current LevelID
+
+	// Links are the connections we've traveled, from each level. Stairs and
+	// portals are recorded in both directions. Falls and level teleports are
+	// one way.
+	Links map[LevelID][]Link
+
+	// Oracle is the floor of the Oracle level, or 0 if we don't know it yet.
+	Oracle int
+
+	// MinesTop and GehennomTop are the first floors of the Mines and
+	// Gehennom. SokobanBottom and TowerBottom are the lowest floors of
+	// Sokoban and Vlad's Tower, which are entered by going up. Each is 0
+	// until we've been there.
+	MinesTop, GehennomTop, SokobanBottom, TowerBottom int
+
+	// last is where the status line said we were on Current.
+	last struct {
+		dlvl           int
+		quest, endgame bool
+	}
+}
+
+// Changed returns whether a says we're no longer on the Current level. The
+// status line is the same on every plane of the endgame, so there the only
+// sign that we've moved on is that we took a portal.
+func (o *Overview) Changed(a Arrival) bool {
+	return o.Current.Branch == "" || a.Dlvl != o.last.dlvl || a.Quest != o.last.quest || a.Endgame != o.last.endgame ||
+		a.Endgame && a.Via == ViaPortal
+}
+
+// Arrive moves the player to a new level and returns its LevelID. levels are
+// the levels we've seen so far. We use the staircases we remember on them to
+// tell when we've taken a branch staircase.
+func (o *Overview) Arrive(a Arrival, levels map[LevelID]*Level) LevelID {
+	from := o.Current
+	to := o.branchFor(a, levels)
+
+	if from.Branch != "" && from != to {
+		o.link(from, to, a.Via)
+		switch a.Via {
+		case ViaStairsDown:
+			o.link(to, from, ViaStairsUp)
+		case ViaStairsUp:
+			o.link(to, from, ViaStairsDown)
+		case ViaPortal:
+			o.link(to, from, ViaPortal)
+		}
+	}
+
+	o.Current = to
+	o.last.dlvl, o.last.quest, o.last.endgame = a.Dlvl, a.Quest, a.Endgame
+	return to
+}
+
+// link records a Link from one level to another, unless we already know it.
+func (o *Overview) link(from, to LevelID, via Via) {
+	for _, l := range o.Links[from] {
+		if l.To == to && l.Via == via {
+			return
+		}
+	}
+	if o.Links == nil {
+		o.Links = make(map[LevelID][]Link)
+	}
+	o.Links[from] = append(o.Links[from], Link{To: to, Via: via})
+}
+
+// branchFor works out which level we've arrived on.
+func (o *Overview) branchFor(a Arrival, levels map[LevelID]*Level) LevelID {
+	from := o.Current
+	here := func(b Branch) LevelID { return LevelID{Branch: b, Floor: a.Dlvl} }
+
+	switch {
+	case a.Endgame:
+		// The status line doesn't tell the planes apart, so we number them
+		// ourselves: Earth is 1 and Astral is 5.
+		if from.Branch == Planes {
+			if a.Via == ViaPortal {
+				return LevelID{Branch: Planes, Floor: from.Floor + 1}
+			}
+			return from
+		}
+		return LevelID{Branch: Planes, Floor: 1}
+	case a.Quest:
+		return here(Quest)
+	case a.Via == ViaPortal && from.Branch == Dungeon:
+		// The only portal in the Dungeon besides the quest portal goes to
+		// Fort Ludios. Every portal out of a branch leads to the Dungeon.
+		return here(Ludios)
+	case a.Via == ViaPortal:
+		return here(Dungeon)
+	}
+
+	if a.Hot && o.GehennomTop == 0 {
+		o.GehennomTop = a.Dlvl
+	}
+
+	switch from.Branch {
+	case Mines:
+		// Falling or teleporting past the bottom of the Mines leaves us on
+		// the bottom, so the only way out is up.
+		if a.Dlvl >= o.MinesTop {
+			return here(Mines)
+		}
+	case Sokoban:
+		if a.Dlvl <= o.SokobanBottom {
+			return here(Sokoban)
+		}
+	case VladsTower:
+		if a.Dlvl <= o.TowerBottom {
+			return here(VladsTower)
+		}
+	}
+
+	if o.GehennomTop != 0 && a.Dlvl >= o.GehennomTop {
+		// Vlad's Tower is reached by a second up staircase on one of the
+		// levels of Gehennom.
+		if from.Branch == Ghennom && a.Via == ViaStairsUp && tookBranch(levels[here(Ghennom)], square.StaircaseDown, a) {
+			o.TowerBottom = a.Dlvl
+			return here(VladsTower)
+		}
+		return here(Ghennom)
+	}
+
+	switch {
+	case a.Via == ViaStairsDown && from.Branch == Dungeon && a.Dlvl >= minesTopMin && a.Dlvl <= minesTopMax && (o.MinesTop == 0 || o.MinesTop == a.Dlvl):
+		if l, ok := levels[here(Dungeon)]; ok && knowsStairs(l, square.StaircaseUp) {
+			if tookBranch(l, square.StaircaseUp, a) {
+				o.MinesTop = a.Dlvl
+				return here(Mines)
+			}
+		} else if a.Gnomes > 0 || a.IrregularWalls {
+			o.MinesTop = a.Dlvl
+			return here(Mines)
+		}
+	case a.Via == ViaStairsUp && from.Branch == Dungeon && a.Dlvl >= oracleMin && a.Dlvl <= oracleMax && (o.Oracle == 0 || o.Oracle == a.Dlvl):
+		if l, ok := levels[here(Dungeon)]; ok && knowsStairs(l, square.StaircaseDown) {
+			if tookBranch(l, square.StaircaseDown, a) {
+				o.SokobanBottom = a.Dlvl
+				return here(Sokoban)
+			}
+		} else if a.Boulders >= sokobanBoulders {
+			o.SokobanBottom = a.Dlvl
+			return here(Sokoban)
+		}
+	}
+	return here(Dungeon)
+}
+
+// knowsStairs returns whether we know where a staircase of kind t is on l.
+func knowsStairs(l *Level, t square.Terrain) bool {
+	_, known := l.stairsAt(t, -1, -1)
+	return known
+}
+
+// tookBranch returns whether we arrived somewhere other than the staircase
+// of kind t that we know about on l. If so, we must be on another level
+// with the same Dlvl: the other end of a branch staircase.
+func tookBranch(l *Level, t square.Terrain, a Arrival) bool {
+	if l == nil {
+		return false
+	}
+	here, known := l.stairsAt(t, a.Y, a.X)
+	return known && !here
+}
@@ -0,0 +1,93 @@
+package level
+
+import (
+	"testing"
+
+	"github.com/jaguilar/nh/model/square"
+	"github.com/stretchr/testify/assert"
+)
+
+// withStairs returns a Level with a staircase of kind t at (y, x).
+func withStairs(id LevelID, t square.Terrain, y, x int) *Level {
+	l := &Level{LevelID: id}
+	l.Map[y][x].Feature = t
+	return l
+}
+
+func TestMines(t *testing.T) {
+	var o Overview
+	levels := map[LevelID]*Level{}
+	down := func(dlvl int, lm Landmarks) LevelID {
+		return o.Arrive(Arrival{Dlvl: dlvl, Via: ViaStairsDown, Landmarks: lm}, levels)
+	}
+
+	assert.Equal(t, LevelID{Dungeon, 1}, o.Arrive(Arrival{Dlvl: 1}, levels))
+	assert.Equal(t, LevelID{Dungeon, 2}, down(2, Landmarks{}))
+	levels[LevelID{Dungeon, 3}] = withStairs(LevelID{Dungeon, 3}, square.StaircaseUp, 5, 5)
+
+	// We know where the up stairs on Dungeon 3 are, so arriving anywhere
+	// else must be the Mines, gnomes or not.
+	assert.Equal(t, LevelID{Dungeon, 3}, down(3, Landmarks{Y: 5, X: 5, Gnomes: 2}))
+	o.Arrive(Arrival{Dlvl: 2, Via: ViaStairsUp}, levels)
+	assert.Equal(t, LevelID{Mines, 3}, down(3, Landmarks{Y: 10, X: 40}))
+	assert.Equal(t, 3, o.MinesTop)
+	assert.Equal(t, LevelID{Mines, 4}, down(4, Landmarks{}))
+
+	// Climbing out of the Mines.
+	o.Arrive(Arrival{Dlvl: 3, Via: ViaStairsUp}, levels)
+	assert.Equal(t, LevelID{Dungeon, 2}, o.Arrive(Arrival{Dlvl: 2, Via: ViaStairsUp}, levels))
+
+	assert.ElementsMatch(t, []Link{
+		{LevelID{Dungeon, 1}, ViaStairsUp},
+		{LevelID{Dungeon, 3}, ViaStairsDown},
+		{LevelID{Mines, 3}, ViaStairsDown},
+	}, o.Links[LevelID{Dungeon, 2}])
+}
+
+func TestMinesByLandmarks(t *testing.T) {
+	var o Overview
+	o.Arrive(Arrival{Dlvl: 2}, nil)
+	assert.Equal(t, LevelID{Mines, 3}, o.Arrive(Arrival{Dlvl: 3, Via: ViaStairsDown, Landmarks: Landmarks{IrregularWalls: true}}, nil))
+}
+
+func TestSokoban(t *testing.T) {
+	var o Overview
+	levels := map[LevelID]*Level{
+		{Dungeon, 6}: withStairs(LevelID{Dungeon, 6}, square.StaircaseDown, 3, 3),
+	}
+	o.Oracle = 6
+	o.Arrive(Arrival{Dlvl: 7}, levels)
+
+	assert.Equal(t, LevelID{Dungeon, 6}, o.Arrive(Arrival{Dlvl: 6, Via: ViaStairsUp, Landmarks: Landmarks{Y: 3, X: 3}}, levels))
+	o.Arrive(Arrival{Dlvl: 7, Via: ViaStairsDown}, levels)
+	assert.Equal(t, LevelID{Sokoban, 6}, o.Arrive(Arrival{Dlvl: 6, Via: ViaStairsUp, Landmarks: Landmarks{Y: 12, X: 30}}, levels))
+	assert.Equal(t, LevelID{Sokoban, 5}, o.Arrive(Arrival{Dlvl: 5, Via: ViaStairsUp}, levels))
+	o.Arrive(Arrival{Dlvl: 6, Via: ViaStairsDown}, levels)
+	assert.Equal(t, LevelID{Dungeon, 7}, o.Arrive(Arrival{Dlvl: 7, Via: ViaStairsDown}, levels))
+
+	// Without knowing the Oracle's stairs, we go by the boulders.
+	o = Overview{}
+	o.Arrive(Arrival{Dlvl: 8}, nil)
+	assert.Equal(t, LevelID{Sokoban, 7}, o.Arrive(Arrival{Dlvl: 7, Via: ViaStairsUp, Landmarks: Landmarks{Boulders: 12}}, nil))
+}
+
+func TestGehennom(t *testing.T) {
+	var o Overview
+	o.Arrive(Arrival{Dlvl: 27}, nil)
+	assert.Equal(t, LevelID{Ghennom, 28}, o.Arrive(Arrival{Dlvl: 28, Via: ViaFall, Landmarks: Landmarks{Hot: true}}, nil))
+	assert.Equal(t, LevelID{Dungeon, 27}, o.Arrive(Arrival{Dlvl: 27, Via: ViaStairsUp}, nil))
+	assert.Equal(t, LevelID{Ghennom, 35}, o.Arrive(Arrival{Dlvl: 35}, nil))
+}
+
+func TestPortals(t *testing.T) {
+	var o Overview
+	o.Arrive(Arrival{Dlvl: 12}, nil)
+	assert.Equal(t, LevelID{Quest, 1}, o.Arrive(Arrival{Dlvl: 1, Quest: true, Via: ViaPortal}, nil))
+	assert.Equal(t, LevelID{Dungeon, 12}, o.Arrive(Arrival{Dlvl: 12, Via: ViaPortal}, nil))
+	assert.Equal(t, LevelID{Ludios, 20}, o.Arrive(Arrival{Dlvl: 20, Via: ViaPortal}, nil))
+	assert.Equal(t, []Link{{LevelID{Dungeon, 12}, ViaPortal}}, o.Links[LevelID{Ludios, 20}])
+
+	assert.Equal(t, LevelID{Planes, 1}, o.Arrive(Arrival{Endgame: true}, nil))
+	assert.Equal(t, LevelID{Planes, 2}, o.Arrive(Arrival{Endgame: true, Via: ViaPortal}, nil))
+	assert.False(t, o.Changed(Arrival{Endgame: true}))
+}
@@ -0,0 +1,229 @@
+package model
+
+import (
+	"github.com/jaguilar/nh/model/command"
+	"github.com/jaguilar/nh/model/event"
+	"github.com/jaguilar/nh/model/internal/screen"
+	"github.com/jaguilar/nh/model/item"
+	"github.com/jaguilar/nh/model/level"
+	"github.com/jaguilar/nh/model/mon"
+	"github.com/jaguilar/nh/model/square"
+)
+
+// Symset is a set of glyphs nethack can draw the map with.
+type Symset int
+
+// The symsets we understand. DECSymset only works if your terminal
+// translates DEC line drawing characters to Unicode.
+const (
+	DefaultSymset Symset = iota
+	IBMSymset
+	DECSymset
+)
+
+var symsets = map[Symset]screen.Symset{
+	DefaultSymset: screen.DefaultSymset,
+	IBMSymset:     screen.IBMSymset,
+	DECSymset:     screen.DECSymset,
+}
+
+// updateMap reads the map from the screen into the current Level, first
+// working out whether we've moved to a different level. It must not be called
+// while a menu covers the map.
+func (g *Game) updateMap(s screen.Screen) {
+	cells := s.Map(symsets[g.opts.Symset], g.vt.Cursor.Y, g.vt.Cursor.X)
+	for y, row := range cells {
+		for x, c := range row {
+			if c.Kind == screen.CellPlayer {
+				g.Player.Y, g.Player.X = y, x
+			}
+		}
+	}
+	g.trackLevel(s, cells)
+
+	id := g.Overview.Current
+	l := g.Level[id]
+	if l == nil {
+		l = &level.Level{LevelID: id}
+		g.Level[id] = l
+	}
+	kills := g.kills(s)
+	for y, row := range cells {
+		for x, c := range row {
+			sq := &l.Map[y][x]
+			wasMonster := sq.Monster != nil
+			observe(sq, c)
+			if wasMonster && len(kills) > 0 && c.Kind == screen.CellObject && c.Category == item.Comestible {
+				sq.Items[0] = g.corpse(kills)
+			}
+		}
+	}
+}
+
+// kills returns the deaths of monsters this turn, including any on the
+// screen's top line.
+func (g *Game) kills(s screen.Screen) []event.Kill {
+	msgs := g.turn
+	if msg, more := s.Message(); !more {
+		msgs = append(append([]string(nil), msgs...), screen.Sentences(msg)...)
+	}
+	var kills []event.Kill
+	for _, m := range msgs {
+		if k, ok := event.Parse(m).(event.Kill); ok {
+			kills = append(kills, k)
+		}
+	}
+	return kills
+}
+
+// corpse returns the corpse we take to have been left where a monster was
+// shown, and now food is, in a turn in which kills happened. It died this
+// turn. If only one monster died, it's of that monster's species, if
+// nethack named it by its species.
+func (g *Game) corpse(kills []event.Kill) item.Item {
+	i := item.Item{Class: g.Registry.ByName("corpse"), Stack: 1, Died: g.Turn}
+	if len(kills) == 1 && mon.Lookup(kills[0].Victim) != nil {
+		i.Species = kills[0].Victim
+	}
+	return i
+}
+
+// trackLevel notices when the status line says we've changed levels, and
+// works out which level we're on now from how we got there and what we can
+// see.
+func (g *Game) trackLevel(s screen.Screen, cells [][]screen.Cell) {
+	if g.Dlvl == 0 && !g.Endgame {
+		// We haven't managed to read the status line yet.
+		return
+	}
+
+	a := level.Arrival{Dlvl: g.Dlvl, Quest: g.Quest, Endgame: g.Endgame}
+	msg, more := s.Message()
+	if more {
+		// Nethack hasn't drawn what happened yet. We'll see the message
+		// again in the turn's events once it has.
+		msg = ""
+	}
+	oracle := false
+	for _, m := range append(append([]string(nil), g.turn...), screen.Sentences(msg)...) {
+		switch e := event.Parse(m).(type) {
+		case event.Fall:
+			a.Via = level.ViaFall
+		case event.Portal:
+			a.Via = level.ViaPortal
+		case event.LevelFeeling:
+			switch e.Feature {
+			case "valley":
+				a.Hot = true
+			case "oracle":
+				oracle = true
+			}
+		}
+	}
+
+	// We may see the portal's message several times before it goes away.
+	// Only the first time means we've moved.
+	portal := a.Via == level.ViaPortal
+	if portal && g.portal {
+		a.Via = level.ViaUnknown
+	}
+	g.portal = portal
+
+	if g.Overview.Changed(a) {
+		if a.Via == level.ViaUnknown {
+			switch g.lastCmd {
+			case command.Up:
+				a.Via = level.ViaStairsUp
+			case command.Down:
+				a.Via = level.ViaStairsDown
+			}
+		}
+		a.Y, a.X = g.Player.Y, g.Player.X
+		a.IrregularWalls = irregularWalls(cells)
+		for _, row := range cells {
+			for _, c := range row {
+				switch {
+				case c.Kind == screen.CellMonster && c.Rune == 'G':
+					a.Gnomes++
+				case c.Kind == screen.CellObject && c.Category == item.Boulder:
+					a.Boulders++
+				}
+			}
+		}
+		g.Overview.Arrive(a, g.Level)
+	}
+
+	if oracle && g.Overview.Current.Branch == level.Dungeon {
+		g.Overview.Oracle = g.Overview.Current.Floor
+	}
+}
+
+// irregularWalls returns whether the walls in cells look like the walls of
+// a cave rather than of rectangular rooms. A straight run of room wall only
+// ever ends at a corner, or at a doorway with more wall beyond it. Cave walls
+// often just stop, with open floor carrying on past the end.
+func irregularWalls(cells [][]screen.Cell) bool {
+	is := func(y, x int, t square.Terrain) bool {
+		if y < 0 || y >= len(cells) || x < 0 || x >= len(cells[y]) {
+			return false
+		}
+		c := cells[y][x]
+		return c.Kind == screen.CellTerrain && c.Terrain == t
+	}
+
+	ends := 0
+	for y, row := range cells {
+		for x := range row {
+			if !is(y, x, square.Wall) {
+				continue
+			}
+			for _, d := range [][2]int{{0, 1}, {0, -1}, {1, 0}, {-1, 0}} {
+				dy, dx := d[0], d[1]
+				if is(y-dy, x-dx, square.Wall) && is(y+dy, x+dx, square.Floor) && !is(y+2*dy, x+2*dx, square.Wall) {
+					ends++
+				}
+			}
+		}
+	}
+	return ends >= caveWallEnds
+}
+
+// caveWallEnds is how many open-ended walls we need to see before we believe
+// we're in a cave. A doorway whose far side we can't see looks like one.
+const caveWallEnds = 3
+
+// observe updates what we know about a Square from what's shown on it.
+//
+// Nethack keeps showing the last thing it saw on a square after it goes out
+// of view. We follow a few rules so that what's remembered doesn't erase
+// what we know:
+//
+//   - Terrain is only learned when it's shown on its own. Objects and
+//     monsters hide the terrain they're on, so they never change it.
+//   - Objects are only forgotten when something else is shown without a
+//     monster in the way.
+//   - Monsters are only believed while they're shown.
+//   - A blank square tells us nothing, since dark floor we've left is
+//     blanked out.
+func observe(sq *square.Square, c screen.Cell) {
+	if c.Kind != screen.CellMonster {
+		sq.Monster = nil
+	}
+
+	switch c.Kind {
+	case screen.CellTerrain:
+		sq.Feature = c.Terrain
+		sq.Items = nil
+	case screen.CellObject:
+		if len(sq.Items) == 0 || sq.Items[0].Class == nil || sq.Items[0].Class.Category != c.Category {
+			// We don't know what the object is, only its category. Once
+			// we've looked at the square, we'll learn more.
+			sq.Items = []item.Item{{Class: &item.Class{Category: c.Category}}}
+		}
+	case screen.CellMonster:
+		class := string(c.Rune)
+		if sq.Monster == nil || sq.Monster.Species == nil || sq.Monster.Species.Class != class {
+			sq.Monster = &mon.Monster{Species: &mon.Species{Class: class}}
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/jaguilar/nh/model/command"
+	"github.com/jaguilar/nh/model/internal/screen"
+	"github.com/jaguilar/nh/model/item"
+	"github.com/jaguilar/nh/model/level"
+	"github.com/jaguilar/nh/model/square"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestObserve(t *testing.T) {
+	var sq square.Square
+
+	// We learn the terrain when it's shown on its own.
+	observe(&sq, screen.Cell{Kind: screen.CellTerrain, Terrain: square.Fountain})
+	assert.Equal(t, square.Fountain, sq.Feature)
+
+	// An object dropped on it doesn't change the terrain.
+	observe(&sq, screen.Cell{Kind: screen.CellObject, Category: item.Weapon})
+	assert.Equal(t, square.Fountain, sq.Feature)
+	if assert.Len(t, sq.Items, 1) {
+		assert.Equal(t, item.Weapon, sq.Items[0].Class.Category)
+	}
+
+	// Neither does a monster standing on the object, and we remember the
+	// object underneath.
+	observe(&sq, screen.Cell{Kind: screen.CellMonster, Rune: 'd'})
+	assert.Equal(t, square.Fountain, sq.Feature)
+	assert.Len(t, sq.Items, 1)
+	if assert.NotNil(t, sq.Monster) {
+		assert.Equal(t, "d", sq.Monster.Species.Class)
+	}
+
+	// Going dark doesn't make us forget anything but the monster.
+	observe(&sq, screen.Cell{Kind: screen.CellBlank, Rune: ' '})
+	assert.Equal(t, square.Fountain, sq.Feature)
+	assert.Len(t, sq.Items, 1)
+	assert.Nil(t, sq.Monster)
+
+	// Seeing the bare terrain means the object is gone.
+	observe(&sq, screen.Cell{Kind: screen.CellTerrain, Terrain: square.Fountain})
+	assert.Empty(t, sq.Items)
+}
+
+// mapOf makes a map from lines of glyphs, as they'd be read from the screen.
+func mapOf(lines ...string) [][]screen.Cell {
+	s := make(screen.Screen, 24)
+	for i := range s {
+		var l string
+		if i > 0 && i <= len(lines) {
+			l = lines[i-1]
+		}
+		s[i] = []rune(l + strings.Repeat(" ", 80-len([]rune(l))))
+	}
+	return s.Map(screen.DefaultSymset, 0, 0)
+}
+
+func TestIrregularWalls(t *testing.T) {
+	room := mapOf(
+		" -----",
+		" |...|",
+		" |....",
+		" |...|",
+		" -----",
+	)
+	cave := mapOf(
+		"   ----     ------",
+		"  --..---  --....|",
+		" --......----..---",
+		" |...........--",
+		" ---....------",
+		"   ------",
+	)
+	assert.False(t, irregularWalls(room))
+	assert.True(t, irregularWalls(cave))
+}
+
+// gameScreen draws the map rows of a level, with msg on the top line and
+// status lines that say the level is where: "Dlvl:1", or "End Game", which
+// is the same on every plane. The cursor is left on the player, as nethack
+// leaves it.
+func gameScreen(msg, where string, rows ...string) string {
+	s := "\x1b[H\x1b[2J" + msg
+	cursor := "\x1b[1;1H"
+	for i, r := range rows {
+		s += fmt.Sprintf("\x1b[%d;1H%s", i+2, r)
+		if x := strings.IndexRune(r, '@'); x >= 0 {
+			cursor = fmt.Sprintf("\x1b[%d;%dH", i+2, x+1)
+		}
+	}
+	return s + "\x1b[23;1HAgent the Valkyrie  St:18 Dx:14 Co:18 In:8 Wi:9 Ch:7 Neutral" +
+		"\x1b[24;1H" + where + " $:0 HP:90(90) Pw:20(20) AC:-5 Xp:14/100000 T:40000" + cursor
+}
+
+func TestPlanes(t *testing.T) {
+	g, f := newTestGame(t)
+	defer f.screen.Close()
+
+	earth := gameScreen("", "End Game", "  .....", "  ..@..", "  .....")
+	f.script = map[string]string{
+		"s": earth,
+		"l": gameScreen("You activated a magic portal!--More--", "End Game", "  .....", "  ...@.", "  ....."),
+		"\r": gameScreen("You feel dizzy for a moment, but the sensation passes.", "End Game",
+			"    ~~~~~", "    ~~@~~", "    ~~~~~"),
+	}
+
+	assert.Nil(t, g.Do(command.Search))
+	assert.Equal(t, level.LevelID{Branch: level.Planes, Floor: 1}, g.Overview.Current)
+
+	assert.Nil(t, g.Do(command.East))
+	assert.Equal(t, level.LevelID{Branch: level.Planes, Floor: 2}, g.Overview.Current)
+	assert.Equal(t, []level.Link{{To: level.LevelID{Branch: level.Planes, Floor: 2}, Via: level.ViaPortal}},
+		g.Overview.Links[level.LevelID{Branch: level.Planes, Floor: 1}])
+
+	// The portal's message is gone, and we stay where we are.
+	f.script["s"] = gameScreen("", "End Game", "    ~~~~~", "    ~~@~~", "    ~~~~~")
+	assert.Nil(t, g.Do(command.Search))
+	assert.Equal(t, level.LevelID{Branch: level.Planes, Floor: 2}, g.Overview.Current)
+}
+
+func TestCorpseDied(t *testing.T) {
+	g, f := newTestGame(t)
+	defer f.screen.Close()
+
+	f.script = map[string]string{
+		"s": gameScreen("", "Dlvl:1", " .....", " .@d.", " ....."),
+		"l": gameScreen("You kill the jackal!", "Dlvl:1", " .....", " .@%.", " ....."),
+	}
+	assert.Nil(t, g.Do(command.Search))
+	assert.Nil(t, g.Do(command.East))
+	corpse := g.Level[g.Overview.Current].Map[g.Y][g.X+1].Items
+	if assert.Len(t, corpse, 1) {
+		assert.Equal(t, "corpse", corpse[0].Class.Name)
+		assert.Equal(t, "jackal", corpse[0].Species)
+		assert.Equal(t, 40000, corpse[0].Died)
+	}
+
+	// We pick it up, and it keeps its turn of death each time we read the
+	// inventory.
+	const clear = "\x1b[H\x1b[2J"
+	f.script = map[string]string{
+		"l":    gameScreen("", "Dlvl:1", " .....", " ..@.", " ....."),
+		",":    gameScreen("a - a jackal corpse.", "Dlvl:1", " .....", " ..@.", " ....."),
+		"i":    clear + " Comestibles\r\n a - a jackal corpse\r\n (end)",
+		"\x1b": gameScreen("", "Dlvl:1", " .....", " ..@.", " ....."),
+	}
+	assert.Nil(t, g.Do(command.East))
+	assert.Nil(t, g.Do(command.PickUp))
+	for n := 0; n < 2; n++ {
+		assert.Nil(t, g.Do(command.Inventory))
+		if assert.Len(t, g.Pack, 1) {
+			assert.Equal(t, "jackal", g.Pack[0].Species)
+			assert.Equal(t, 40000, g.Pack[0].Died)
+		}
+	}
+}
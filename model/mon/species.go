@@ -1,10 +1,135 @@
 package mon
 
 import (
+	"strings"
+
 	"github.com/jaguilar/nh/model/anatomy"
+	"github.com/jaguilar/nh/model/item"
 )
 
 type Species struct {
 	Name, Class string
 	*anatomy.Anatomy
+
+	// Level is the species' base level, and AC its armor class.
+	Level, AC int
+	Size
+}
+
+// speciesData lists the species in each monster class, by the symbol nethack
+// draws them with. Lycanthropes appear under both their forms. The human
+// and elf classes, '@', include the player monsters and quest characters.
+var speciesData = map[string]string{
+	"a": "giant ant|killer bee|soldier ant|fire ant|giant beetle|queen bee",
+	"b": "acid blob|quivering blob|gelatinous cube",
+	"c": "chickatrice|cockatrice|pyrolisk",
+	"d": "jackal|fox|coyote|werejackal|little dog|dingo|dog|large dog|wolf|werewolf|" +
+		"warg|winter wolf cub|winter wolf|hell hound pup|hell hound",
+	"e": "gas spore|floating eye|freezing sphere|flaming sphere|shocking sphere",
+	"f": "kitten|housecat|jaguar|lynx|panther|large cat|tiger",
+	"g": "gremlin|gargoyle|winged gargoyle",
+	"h": "hobbit|dwarf|bugbear|dwarf lord|dwarf king|mind flayer|master mind flayer",
+	"i": "manes|homunculus|imp|lemure|quasit|tengu",
+	"j": "blue jelly|spotted jelly|ochre jelly",
+	"k": "kobold|large kobold|kobold lord|kobold shaman",
+	"l": "leprechaun",
+	"m": "small mimic|large mimic|giant mimic",
+	"n": "wood nymph|water nymph|mountain nymph",
+	"o": "goblin|hobgoblin|orc|hill orc|Mordor orc|Uruk-hai|orc shaman|orc-captain",
+	"p": "rock piercer|iron piercer|glass piercer",
+	"q": "rothe|mumak|leocrotta|wumpus|titanothere|baluchitherium|mastodon",
+	"r": "sewer rat|giant rat|rabid rat|wererat|rock mole|woodchuck",
+	"s": "cave spider|centipede|giant spider|scorpion",
+	"t": "lurker above|trapper",
+	"u": "pony|white unicorn|gray unicorn|black unicorn|horse|warhorse",
+	"v": "fog cloud|dust vortex|ice vortex|energy vortex|steam vortex|fire vortex",
+	"w": "baby long worm|baby purple worm|long worm|purple worm",
+	"x": "grid bug|xan",
+	"y": "yellow light|black light",
+	"z": "zruty",
+	"A": "couatl|Aleax|Angel|ki-rin|Archon",
+	"B": "bat|giant bat|raven|vampire bat",
+	"C": "plains centaur|forest centaur|mountain centaur",
+	"D": "baby gray dragon|baby silver dragon|baby red dragon|baby white dragon|" +
+		"baby orange dragon|baby black dragon|baby blue dragon|baby green dragon|" +
+		"baby yellow dragon|gray dragon|silver dragon|red dragon|white dragon|" +
+		"orange dragon|black dragon|blue dragon|green dragon|yellow dragon|" +
+		"Chromatic Dragon|Ixoth",
+	"E": "stalker|air elemental|fire elemental|earth elemental|water elemental",
+	"F": "lichen|brown mold|yellow mold|green mold|red mold|shrieker|violet fungus",
+	"G": "gnome|gnome lord|gnomish wizard|gnome king",
+	"H": "giant|stone giant|hill giant|fire giant|frost giant|storm giant|ettin|titan|minotaur|Cyclops",
+	"J": "jabberwock",
+	"K": "Keystone Kop|Kop Sergeant|Kop Lieutenant|Kop Kaptain",
+	"L": "lich|demilich|master lich|arch-lich",
+	"M": "kobold mummy|gnome mummy|orc mummy|dwarf mummy|elf mummy|human mummy|" +
+		"ettin mummy|giant mummy",
+	"N": "red naga hatchling|black naga hatchling|golden naga hatchling|" +
+		"guardian naga hatchling|red naga|black naga|golden naga|guardian naga",
+	"O": "ogre|ogre lord|ogre king",
+	"P": "gray ooze|brown pudding|black pudding|green slime",
+	"Q": "quantum mechanic",
+	"R": "rust monster|disenchanter",
+	"S": "garter snake|snake|water moccasin|pit viper|python|cobra",
+	"T": "troll|ice troll|rock troll|water troll|Olog-hai",
+	"U": "umber hulk",
+	"V": "vampire|vampire lord|Vlad the Impaler",
+	"W": "barrow wight|wraith|Nazgul",
+	"X": "xorn",
+	"Y": "monkey|ape|owlbear|yeti|carnivorous ape|sasquatch",
+	"Z": "kobold zombie|gnome zombie|orc zombie|dwarf zombie|elf zombie|human zombie|" +
+		"ettin zombie|giant zombie|ghoul|skeleton",
+	"&": "water demon|horned devil|succubus|incubus|erinys|barbed devil|marilith|" +
+		"vrock|hezrou|bone devil|ice devil|nalfeshnee|pit fiend|balrog|Juiblex|" +
+		"Yeenoghu|Orcus|Geryon|Dispater|Baalzebub|Asmodeus|Demogorgon|Death|" +
+		"Pestilence|Famine|mail daemon|djinni|Minion of Huhetotl",
+	"'": "straw golem|paper golem|rope golem|gold golem|leather golem|wood golem|" +
+		"flesh golem|clay golem|stone golem|glass golem|iron golem",
+	";": "jellyfish|piranha|shark|giant eel|electric eel|kraken",
+	":": "newt|gecko|iguana|baby crocodile|lizard|chameleon|crocodile|salamander",
+	" ": "ghost|shade",
+	"@": "human|wererat|werejackal|werewolf|elf|Woodland-elf|Green-elf|Grey-elf|" +
+		"elf-lord|Elvenking|doppelganger|nurse|mugger|shopkeeper|guard|prisoner|Oracle|" +
+		"aligned priest|high priest|soldier|sergeant|lieutenant|captain|watchman|" +
+		"watch captain|Medusa|Wizard of Yendor|Croesus|" +
+		"archeologist|barbarian|caveman|cavewoman|healer|knight|monk|priest|" +
+		"priestess|ranger|rogue|samurai|tourist|valkyrie|wizard|" +
+		"Lord Carnarvon|Pelias|Shaman Karnov|Hippocrates|King Arthur|Grand Master|" +
+		"Arch Priest|Orion|Master of Thieves|Lord Sato|Twoflower|Norn|" +
+		"Neferet the Green|student|chieftain|neanderthal|attendant|page|abbot|" +
+		"acolyte|hunter|thug|ninja|roshi|guide|warrior|apprentice|" +
+		"Thoth Amon|Master Kaen|Nalzok|Scorpius|Master Assassin|" +
+		"Ashikaga Takauji|Lord Surtur|Dark One",
+}
+
+// species maps each species' name to it. A lycanthrope's name maps to its
+// human form.
+var species = make(map[string]*Species)
+
+func init() {
+	for class, names := range speciesData {
+		for _, n := range strings.Split(names, "|") {
+			if s, ok := species[n]; ok && s.Class == "@" {
+				continue
+			}
+			s := &Species{Name: n, Class: class}
+			if class == "@" {
+				s.Anatomy = anatomy.Humanoid
+			}
+			species[n] = s
+		}
+	}
+}
+
+// Lookup returns the species with a name, or nil if there's no such species.
+func Lookup(name string) *Species {
+	return species[name]
+}
+
+// SpeciesOf returns the species an item is of, or made from: a corpse's
+// monster, a tin's meat, a statue's subject. It returns nil for other items,
+// and for those whose species we don't know, like an egg we haven't
+// identified.
+func SpeciesOf(i *item.Item) *Species {
+	return species[i.Species]
 }
@@ -0,0 +1,42 @@
+package mon
+
+import (
+	"testing"
+
+	"github.com/jaguilar/nh/model/item"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLookup(t *testing.T) {
+	if assert.NotNil(t, Lookup("newt")) {
+		assert.Equal(t, "newt", Lookup("newt").Name)
+	}
+	assert.Nil(t, Lookup("spinach"))
+
+	r := item.NewRegistry()
+	i, err := r.Parse("a - a cockatrice corpse")
+	if assert.Nil(t, err) {
+		assert.True(t, SpeciesOf(i) == Lookup("cockatrice"))
+	}
+}
+
+func TestStats(t *testing.T) {
+	for n, s := range species {
+		assert.False(t, s.Level == 0 && s.AC == 0, "no stats for %s", n)
+	}
+	s := Lookup("vampire lord")
+	assert.Equal(t, 12, s.Level)
+	assert.Equal(t, 0, s.AC)
+	assert.True(t, s.Undead())
+	assert.True(t, s.HatesSilver())
+	assert.False(t, s.Demon())
+	assert.False(t, s.Large())
+
+	assert.True(t, Lookup("werewolf").HatesSilver())
+	assert.True(t, Lookup("hill orc").Orc())
+	assert.True(t, Lookup("balrog").Demon())
+	assert.False(t, Lookup("djinni").Demon())
+	assert.False(t, Lookup("tengu").HatesSilver())
+	assert.True(t, Lookup("purple worm").Large())
+	assert.False(t, Lookup("minotaur").Giant())
+}
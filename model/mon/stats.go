@@ -0,0 +1,481 @@
+package mon
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Size is how big a species is.
+type Size int
+
+// The sizes, as nethack has them. Human-sized is Medium.
+const (
+	Tiny Size = iota
+	Small
+	Medium
+	Large
+	Huge
+	Gigantic Size = 7
+)
+
+var sizes = map[string]Size{
+	"tiny": Tiny, "small": Small, "medium": Medium, "large": Large,
+	"huge": Huge, "gigantic": Gigantic,
+}
+
+func init() {
+	// Each line is "name,level,AC,size", from nethack 3.4.3's monst.c.
+	// Lycanthropes have the stats of their human forms.
+	data := `giant ant,2,3,tiny
+killer bee,1,-1,tiny
+soldier ant,3,3,tiny
+fire ant,3,3,tiny
+giant beetle,5,4,large
+queen bee,9,-4,tiny
+acid blob,1,8,tiny
+quivering blob,5,8,small
+gelatinous cube,6,8,large
+chickatrice,4,8,tiny
+cockatrice,5,6,small
+pyrolisk,6,6,small
+jackal,0,7,small
+fox,1,7,small
+coyote,1,7,small
+werejackal,2,10,medium
+little dog,2,6,small
+dingo,4,7,medium
+dog,4,5,medium
+large dog,6,4,medium
+wolf,5,4,medium
+werewolf,5,10,medium
+warg,7,4,medium
+winter wolf cub,5,4,small
+winter wolf,7,4,large
+hell hound pup,7,4,small
+hell hound,12,2,medium
+gas spore,1,10,small
+floating eye,2,9,small
+freezing sphere,6,4,small
+flaming sphere,6,4,small
+shocking sphere,6,4,small
+kitten,0,6,small
+housecat,4,5,small
+jaguar,4,6,large
+lynx,5,6,small
+panther,5,6,large
+large cat,6,4,small
+tiger,6,6,large
+gremlin,5,2,small
+gargoyle,6,-4,medium
+winged gargoyle,9,-2,medium
+hobbit,1,10,small
+dwarf,2,10,medium
+bugbear,3,5,large
+dwarf lord,4,10,medium
+dwarf king,6,10,medium
+mind flayer,9,5,medium
+master mind flayer,13,0,medium
+manes,1,7,small
+homunculus,2,6,tiny
+imp,3,2,tiny
+lemure,3,7,medium
+quasit,3,2,small
+tengu,6,5,small
+blue jelly,4,8,medium
+spotted jelly,5,8,medium
+ochre jelly,6,8,medium
+kobold,0,10,small
+large kobold,1,10,small
+kobold lord,2,10,small
+kobold shaman,2,6,small
+leprechaun,5,8,tiny
+small mimic,7,7,medium
+large mimic,8,7,large
+giant mimic,9,7,large
+wood nymph,3,9,medium
+water nymph,3,9,medium
+mountain nymph,3,9,medium
+goblin,0,10,small
+hobgoblin,1,10,medium
+orc,1,10,medium
+hill orc,1,10,medium
+Mordor orc,3,10,medium
+Uruk-hai,3,10,medium
+orc shaman,3,5,medium
+orc-captain,5,10,medium
+rock piercer,3,3,small
+iron piercer,5,0,medium
+glass piercer,7,0,medium
+rothe,2,7,large
+mumak,5,0,large
+leocrotta,6,4,large
+wumpus,8,2,large
+titanothere,12,6,large
+baluchitherium,14,5,large
+mastodon,20,5,large
+sewer rat,0,7,tiny
+giant rat,1,7,tiny
+rabid rat,2,6,tiny
+wererat,2,10,medium
+rock mole,3,0,small
+woodchuck,3,5,small
+cave spider,1,3,tiny
+centipede,2,3,tiny
+giant spider,5,4,large
+scorpion,5,3,small
+lurker above,10,3,huge
+trapper,12,3,huge
+pony,2,6,medium
+white unicorn,4,2,large
+gray unicorn,4,2,large
+black unicorn,4,2,large
+horse,5,5,large
+warhorse,7,4,large
+fog cloud,3,0,huge
+dust vortex,4,2,huge
+ice vortex,5,2,huge
+energy vortex,6,2,huge
+steam vortex,7,2,huge
+fire vortex,8,2,huge
+baby long worm,8,5,large
+baby purple worm,8,5,large
+long worm,8,5,gigantic
+purple worm,15,6,gigantic
+grid bug,0,9,tiny
+xan,7,-4,tiny
+yellow light,3,0,small
+black light,5,0,small
+zruty,9,3,large
+couatl,8,5,large
+Aleax,10,0,medium
+Angel,14,-4,medium
+ki-rin,16,-5,large
+Archon,19,-6,large
+bat,0,8,tiny
+giant bat,2,7,small
+raven,4,6,small
+vampire bat,5,6,small
+plains centaur,4,4,large
+forest centaur,5,3,large
+mountain centaur,6,2,large
+baby gray dragon,12,2,huge
+baby silver dragon,12,2,huge
+baby red dragon,12,2,huge
+baby white dragon,12,2,huge
+baby orange dragon,12,2,huge
+baby black dragon,12,2,huge
+baby blue dragon,12,2,huge
+baby green dragon,12,2,huge
+baby yellow dragon,12,2,huge
+gray dragon,15,-1,gigantic
+silver dragon,15,-1,gigantic
+red dragon,15,-1,gigantic
+white dragon,15,-1,gigantic
+orange dragon,15,-1,gigantic
+black dragon,15,-1,gigantic
+blue dragon,15,-1,gigantic
+green dragon,15,-1,gigantic
+yellow dragon,15,-1,gigantic
+Chromatic Dragon,16,0,gigantic
+Ixoth,15,-1,gigantic
+stalker,3,3,large
+air elemental,8,2,huge
+fire elemental,8,2,huge
+earth elemental,8,2,huge
+water elemental,8,2,huge
+lichen,0,9,small
+brown mold,1,9,small
+yellow mold,1,9,small
+green mold,1,9,small
+red mold,3,9,small
+shrieker,2,7,small
+violet fungus,3,7,small
+gnome,1,10,small
+gnome lord,3,10,small
+gnomish wizard,3,4,small
+gnome king,5,10,small
+giant,6,0,huge
+stone giant,6,0,huge
+hill giant,8,6,huge
+fire giant,9,4,huge
+frost giant,10,3,huge
+storm giant,16,3,huge
+ettin,10,3,huge
+titan,16,-3,huge
+minotaur,15,6,large
+Cyclops,18,0,huge
+jabberwock,15,-2,large
+Keystone Kop,1,10,medium
+Kop Sergeant,2,10,medium
+Kop Lieutenant,3,10,medium
+Kop Kaptain,4,10,medium
+lich,11,0,medium
+demilich,14,-2,medium
+master lich,17,-4,medium
+arch-lich,25,-6,medium
+kobold mummy,3,6,small
+gnome mummy,4,6,small
+orc mummy,5,5,medium
+dwarf mummy,5,5,medium
+elf mummy,6,4,medium
+human mummy,6,4,medium
+ettin mummy,7,4,huge
+giant mummy,8,3,huge
+red naga hatchling,3,6,large
+black naga hatchling,3,6,large
+golden naga hatchling,3,6,large
+guardian naga hatchling,3,6,large
+red naga,6,4,huge
+black naga,8,2,huge
+golden naga,10,2,huge
+guardian naga,12,0,huge
+ogre,5,5,large
+ogre lord,7,3,large
+ogre king,9,4,large
+gray ooze,3,8,medium
+brown pudding,5,8,medium
+black pudding,10,6,large
+green slime,6,6,large
+quantum mechanic,7,3,medium
+rust monster,5,2,medium
+disenchanter,12,-10,large
+garter snake,1,8,tiny
+snake,4,3,small
+water moccasin,4,3,small
+pit viper,6,2,medium
+python,6,5,huge
+cobra,6,2,medium
+troll,7,4,large
+ice troll,9,2,large
+rock troll,9,0,large
+water troll,11,4,large
+Olog-hai,13,-4,large
+umber hulk,9,2,large
+vampire,10,1,medium
+vampire lord,12,0,medium
+Vlad the Impaler,14,-3,medium
+barrow wight,3,5,medium
+wraith,6,4,medium
+Nazgul,13,0,medium
+xorn,8,-2,medium
+monkey,2,6,small
+ape,4,6,large
+owlbear,5,5,large
+yeti,5,6,large
+carnivorous ape,6,6,large
+sasquatch,7,6,large
+kobold zombie,0,10,small
+gnome zombie,1,10,small
+orc zombie,2,9,medium
+dwarf zombie,2,9,medium
+elf zombie,3,9,medium
+human zombie,4,8,medium
+ettin zombie,6,6,huge
+giant zombie,8,6,huge
+ghoul,3,4,small
+skeleton,12,4,medium
+water demon,8,-4,medium
+horned devil,6,-5,medium
+succubus,6,0,medium
+incubus,6,0,medium
+erinys,7,2,medium
+barbed devil,8,0,medium
+marilith,7,-6,large
+vrock,8,0,large
+hezrou,9,-2,large
+bone devil,9,-1,large
+ice devil,11,-4,large
+nalfeshnee,11,-1,large
+pit fiend,13,-3,large
+balrog,16,-2,large
+Juiblex,50,-7,large
+Yeenoghu,56,-5,large
+Orcus,66,-6,huge
+Geryon,72,-3,huge
+Dispater,78,-2,medium
+Baalzebub,89,-5,large
+Asmodeus,105,-7,huge
+Demogorgon,106,-8,huge
+Death,30,-5,medium
+Pestilence,30,-5,medium
+Famine,30,-5,medium
+mail daemon,56,-4,medium
+djinni,7,4,medium
+Minion of Huhetotl,16,-2,large
+straw golem,3,10,large
+paper golem,3,10,large
+rope golem,4,8,large
+gold golem,5,6,large
+leather golem,6,6,large
+wood golem,7,4,large
+flesh golem,9,9,large
+clay golem,11,7,large
+stone golem,14,5,large
+glass golem,16,1,large
+iron golem,18,3,large
+jellyfish,3,6,small
+piranha,5,4,small
+shark,7,2,large
+giant eel,5,-1,huge
+electric eel,7,-3,huge
+kraken,20,6,huge
+newt,0,8,tiny
+gecko,1,8,tiny
+iguana,2,7,tiny
+baby crocodile,6,7,medium
+lizard,5,6,tiny
+chameleon,6,6,tiny
+crocodile,6,5,large
+salamander,8,-1,medium
+ghost,10,-5,medium
+shade,12,10,medium
+human,0,10,medium
+elf,10,10,medium
+Woodland-elf,4,10,medium
+Green-elf,5,10,medium
+Grey-elf,6,10,medium
+elf-lord,8,10,medium
+Elvenking,9,10,medium
+doppelganger,9,5,medium
+nurse,11,0,medium
+mugger,2,10,medium
+shopkeeper,12,0,medium
+guard,12,10,medium
+prisoner,12,10,medium
+Oracle,12,0,medium
+aligned priest,12,10,medium
+high priest,25,7,medium
+soldier,6,10,medium
+sergeant,8,10,medium
+lieutenant,10,10,medium
+captain,12,10,medium
+watchman,10,10,medium
+watch captain,12,10,medium
+Medusa,20,2,large
+Wizard of Yendor,30,-8,medium
+Croesus,20,0,medium
+archeologist,10,10,medium
+barbarian,10,10,medium
+caveman,10,10,medium
+cavewoman,10,10,medium
+healer,10,10,medium
+knight,10,10,medium
+monk,10,10,medium
+priest,10,10,medium
+priestess,10,10,medium
+ranger,10,10,medium
+rogue,10,10,medium
+samurai,10,10,medium
+tourist,10,10,medium
+valkyrie,10,10,medium
+wizard,10,10,medium
+Lord Carnarvon,20,0,medium
+Pelias,20,0,medium
+Shaman Karnov,20,0,medium
+Hippocrates,20,0,medium
+King Arthur,20,0,medium
+Grand Master,25,10,medium
+Arch Priest,25,7,medium
+Orion,20,0,medium
+Master of Thieves,20,10,medium
+Lord Sato,20,0,medium
+Twoflower,20,10,medium
+Norn,20,0,medium
+Neferet the Green,20,0,medium
+student,5,10,medium
+chieftain,5,10,medium
+neanderthal,5,10,medium
+attendant,5,10,medium
+page,5,10,medium
+abbot,5,10,medium
+acolyte,5,10,medium
+hunter,5,10,medium
+thug,5,10,medium
+ninja,5,10,medium
+roshi,5,10,medium
+guide,5,10,medium
+warrior,5,10,medium
+apprentice,5,10,medium
+Thoth Amon,16,0,medium
+Master Kaen,25,-10,medium
+Nalzok,16,-2,large
+Scorpius,15,10,medium
+Master Assassin,15,0,medium
+Ashikaga Takauji,15,0,medium
+Lord Surtur,15,2,huge
+Dark One,15,0,medium`
+	for _, line := range strings.Split(data, "\n") {
+		f := strings.Split(line, ",")
+		s, ok := species[f[0]]
+		if !ok {
+			panic(fmt.Sprintf("stats for unknown species %q", f[0]))
+		}
+		var err error
+		if s.Level, err = strconv.Atoi(f[1]); err != nil {
+			panic(err)
+		}
+		if s.AC, err = strconv.Atoi(f[2]); err != nil {
+			panic(err)
+		}
+		if s.Size, ok = sizes[f[3]]; !ok {
+			panic(fmt.Sprintf("unknown size %q for %s", f[3], f[0]))
+		}
+	}
+}
+
+// Large returns whether weapons do their large-monster damage to s.
+func (s *Species) Large() bool {
+	return s.Size >= Large
+}
+
+var (
+	// undeadClasses are the classes whose members are all undead.
+	undeadClasses = "LMVWZ "
+
+	// The members of other classes that have flags their classmates don't.
+	demons = set("water demon|horned devil|succubus|incubus|erinys|barbed devil|" +
+		"marilith|vrock|hezrou|bone devil|ice devil|nalfeshnee|pit fiend|balrog|" +
+		"Juiblex|Yeenoghu|Orcus|Geryon|Dispater|Baalzebub|Asmodeus|Demogorgon|" +
+		"mail daemon|Minion of Huhetotl|Nalzok")
+	weres  = set("werejackal|wererat|werewolf")
+	giants = set("giant|stone giant|hill giant|fire giant|frost giant|storm giant|" +
+		"ettin|titan|Cyclops|Lord Surtur")
+	elves = set("elf|Woodland-elf|Green-elf|Grey-elf|elf-lord|Elvenking")
+)
+
+func set(names string) map[string]bool {
+	m := make(map[string]bool)
+	for _, n := range strings.Split(names, "|") {
+		m[n] = true
+	}
+	return m
+}
+
+// Demon returns whether s is a demon. The Riders and djinn aren't.
+func (s *Species) Demon() bool { return demons[s.Name] }
+
+// Undead returns whether s is undead.
+func (s *Species) Undead() bool { return strings.Contains(undeadClasses, s.Class) }
+
+// Were returns whether s is a lycanthrope.
+func (s *Species) Were() bool { return weres[s.Name] }
+
+// Orc returns whether s is an orc.
+func (s *Species) Orc() bool { return s.Class == "o" }
+
+// Elf returns whether s is an elf.
+func (s *Species) Elf() bool { return elves[s.Name] }
+
+// Giant returns whether s is a giant. Minotaurs aren't.
+func (s *Species) Giant() bool { return giants[s.Name] }
+
+// Dragon returns whether s is a dragon.
+func (s *Species) Dragon() bool { return s.Class == "D" }
+
+// HatesSilver returns whether silver does s extra damage: demons,
+// lycanthropes, vampires, shades and imps other than tengu.
+func (s *Species) HatesSilver() bool {
+	return s.Demon() || s.Were() || s.Class == "V" || s.Name == "shade" ||
+		s.Class == "i" && s.Name != "tengu"
+}
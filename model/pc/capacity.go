@@ -0,0 +1,80 @@
+package pc
+
+import (
+	"github.com/jaguilar/nh/model/item"
+	"github.com/jaguilar/nh/model/mon"
+)
+
+// maxCapacity is the most anyone can carry without being burdened.
+const maxCapacity = 1000
+
+// strength returns the player's Str on nethack's 3 to 25 scale: 18/01
+// through 18/31 count as 19, up to 18/81 as 20, and above that as 21.
+func (p *Player) strength() int {
+	switch {
+	case p.Str <= 18:
+		return p.Str
+	case p.Str <= 121:
+		return 19 + p.Str/50
+	}
+	return p.Str - 100
+}
+
+// Capacity returns how much weight the player can carry before they're
+// Burdened, as nethack's weight_cap works it out from their Str and Con.
+// Levitating players can carry the most there is. Nymphs can too.
+//
+// We don't know how heavy monsters are, so the capacity of a player
+// polymorphed into something other than a nymph is scaled by its size, as
+// nethack does for monsters that leave no corpse.
+func (p *Player) Capacity() int {
+	c := 25*(p.strength()+p.Con) + 50
+	if s := p.Species; s != nil && s.Class != "@" {
+		if s.Class == "n" {
+			c = maxCapacity
+		} else {
+			c = c * int(s.Size) / int(mon.Medium)
+		}
+	}
+	if p.Conditions.Has(Lev) || c > maxCapacity {
+		c = maxCapacity
+	}
+	return c
+}
+
+// Load returns the weight of what the player is carrying: the pack,
+// including what's in its containers, and the gold. The gold is weighed
+// from the status line, so any gold listed in the pack is skipped.
+func (p *Player) Load() int {
+	w := (p.Gold + 50) / 100
+	for _, i := range p.Pack {
+		if i.Class.Category == item.Coins {
+			continue
+		}
+		w += i.Weight()
+	}
+	return w
+}
+
+// EncumbranceWith returns how encumbered the player would be carrying extra
+// more weight than they are, as nethack's calc_capacity works it out.
+func (p *Player) EncumbranceWith(extra int) Encumbrance {
+	c := p.Capacity()
+	over := p.Load() + extra - c
+	switch {
+	case over <= 0:
+		return Unencumbered
+	case c <= 1:
+		return Overloaded
+	}
+	if e := Encumbrance(over*2/c + 1); e < Overloaded {
+		return e
+	}
+	return Overloaded
+}
+
+// WouldBe returns how encumbered the player would be after picking up i.
+// An autopickup can check that it's no worse than Burdened first.
+func (p *Player) WouldBe(i *item.Item) Encumbrance {
+	return p.EncumbranceWith(i.Weight())
+}
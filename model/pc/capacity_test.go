@@ -0,0 +1,64 @@
+package pc
+
+import (
+	"testing"
+
+	"github.com/jaguilar/nh/model/item"
+	"github.com/jaguilar/nh/model/mon"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCapacity(t *testing.T) {
+	p := &Player{Str: 16, Con: 14}
+	assert.Equal(t, 800, p.Capacity())
+
+	p.Str = Str18(50)
+	assert.Equal(t, 25*(20+14)+50, p.Capacity())
+	p.Str = 125
+	assert.Equal(t, 1000, p.Capacity())
+
+	p.Str, p.Con = 3, 3
+	assert.Equal(t, 200, p.Capacity())
+	p.Conditions = Lev
+	assert.Equal(t, 1000, p.Capacity())
+	p.Conditions = 0
+
+	p.Species = mon.Lookup("newt")
+	assert.Equal(t, 0, p.Capacity())
+	p.Species = mon.Lookup("water nymph")
+	assert.Equal(t, 1000, p.Capacity())
+	p.Species = mon.Lookup("human")
+	assert.Equal(t, 200, p.Capacity())
+}
+
+func TestEncumbrance(t *testing.T) {
+	r := item.NewRegistry()
+	parse := func(s string) *item.Item {
+		i, err := r.Parse(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return i
+	}
+
+	// A capacity of 800.
+	p := &Player{Str: 16, Con: 14, Gold: 250}
+	p.Pack = []*item.Item{parse("a - a +0 plate mail"), parse("b - 10 rocks")}
+	assert.Equal(t, 450+100+3, p.Load())
+	assert.Equal(t, Unencumbered, p.EncumbranceWith(0))
+
+	// The inventory lists the gold on the status line too.
+	p.Pack = append([]*item.Item{parse("$ - 250 gold pieces")}, p.Pack...)
+	assert.Equal(t, 450+100+3, p.Load())
+
+	assert.Equal(t, Burdened, p.WouldBe(parse("c - a +0 plate mail")))
+	assert.Equal(t, Stressed, p.WouldBe(parse("c - 70 rocks")))
+	assert.Equal(t, Overloaded, p.WouldBe(parse("c - a boulder")))
+
+	// Weight in a bag of holding counts for half.
+	bag := parse("d - an uncursed bag of holding")
+	bag.Contents = []*item.Item{parse("e - a +0 plate mail")}
+	p.Pack = append(p.Pack, bag)
+	assert.Equal(t, 450+100+3+15+225, p.Load())
+	assert.Equal(t, Unencumbered, p.EncumbranceWith(0))
+}
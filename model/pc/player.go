@@ -22,10 +22,80 @@ const (
 	Cursed
 )
 
+// Hunger is the player's hunger status, as shown on the status line.
+type Hunger int
+
+// The hunger states, from least to most hungry.
+const (
+	NotHungry Hunger = iota
+	Satiated
+	Hungry
+	Weak
+	Fainting
+	Fainted
+)
+
+// Encumbrance is how much the player's load is slowing them down.
+type Encumbrance int
+
+// The encumbrance levels, from least to most encumbered.
+const (
+	Unencumbered Encumbrance = iota
+	Burdened
+	Stressed
+	Strained
+	Overtaxed
+	Overloaded
+)
+
+// Condition is a set of status conditions that nethack shows on the status
+// line.
+type Condition uint
+
+// The conditions. Ill is terminal illness; FoodPois is food poisoning.
+const (
+	Conf Condition = 1 << iota
+	Stun
+	Blind
+	Hallu
+	Ill
+	FoodPois
+	Slime
+	Stone
+	Strangled
+	Deaf
+	Lev
+	Fly
+	Ride
+)
+
+// Has returns whether all the conditions in o are also in c.
+func (c Condition) Has(o Condition) bool {
+	return c&o == o
+}
+
+// Str18 returns the encoding of an 18/xx strength. Strength is encoded the
+// same way nethack does it internally: 3 through 18 are themselves, 18/01
+// through 18/99 are 19 through 117, 18/** is 118, and 19 through 25 are 119
+// through 125.
+func Str18(x int) int {
+	return 18 + x
+}
+
 type Player struct {
 	Name string
 
-	Hp, Pow, HpMax, PowMax       int
+	// Title is the player's rank title ("Stripling"), or the name of the
+	// monster they are polymorphed into.
+	Title string
+
+	// Role is worked out from the rank Title. It's kept while the player is
+	// polymorphed, since the title doesn't tell us then.
+	Role
+
+	Hp, Pow, HpMax, PowMax int
+
+	// Str is encoded as described on Str18.
 	Str, Dex, Con, Int, Wis, Cha int
 	Alignment
 
@@ -37,4 +107,34 @@ type Player struct {
 
 	Gold int
 	Pack []*item.Item
+
+	// Spells are the spells the Player knows, as of the last time we saw
+	// the spell menu.
+	Spells []Spell
+
+	// Dlvl is the dungeon level shown on the status line. In the quest, it's
+	// the quest level ("Home 3"), and Quest is set. In the endgame, it's zero
+	// and Endgame is set.
+	Dlvl           int
+	Quest, Endgame bool
+
+	AC int
+
+	// XL is the experience level. When the player is polymorphed, the
+	// status line shows hit dice instead, and those are stored here.
+	XL int
+
+	// Exp is the experience point count. It's only shown with the showexp
+	// option.
+	Exp int
+
+	// Turn is the turn counter. It's only shown with the time option.
+	Turn int
+
+	Hunger
+	Encumbrance
+	Conditions Condition
+
+	// Y and X are the player's position on the current level's Map.
+	Y, X int
 }
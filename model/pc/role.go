@@ -0,0 +1,76 @@
+package pc
+
+import "strings"
+
+// Role is the player's role (class), like Valkyrie or Wizard.
+type Role string
+
+// The roles.
+const (
+	RoleUnknown  Role = ""
+	Archeologist Role = "Archeologist"
+	Barbarian    Role = "Barbarian"
+	Caveman      Role = "Caveman"
+	Healer       Role = "Healer"
+	Knight       Role = "Knight"
+	Monk         Role = "Monk"
+	Priest       Role = "Priest"
+	Rogue        Role = "Rogue"
+	Ranger       Role = "Ranger"
+	Samurai      Role = "Samurai"
+	Tourist      Role = "Tourist"
+	Valkyrie     Role = "Valkyrie"
+	Wizard       Role = "Wizard"
+)
+
+// rankTitles are each role's rank titles, from XL 1 up. Where the female
+// title differs, it follows the male one after a slash.
+var rankTitles = map[Role][]string{
+	Archeologist: {"Digger", "Field Worker", "Investigator", "Exhumer", "Excavator",
+		"Spelunker", "Speleologist", "Collector", "Curator"},
+	Barbarian: {"Plunderer/Plunderess", "Pillager", "Bandit", "Brigand", "Raider",
+		"Reaver", "Slayer", "Chieftain/Chieftainess", "Conqueror/Conqueress"},
+	Caveman: {"Troglodyte", "Aborigine", "Wanderer", "Vagrant", "Wayfarer",
+		"Roamer", "Nomad", "Rover", "Pioneer"},
+	Healer: {"Rhizotomist", "Empiric", "Embalmer", "Dresser", "Medicus ossium/Medica ossium",
+		"Herbalist", "Magister/Magistra", "Physician", "Chirurgeon"},
+	Knight: {"Gallant", "Esquire", "Bachelor", "Sergeant", "Knight",
+		"Banneret", "Chevalier/Chevaliere", "Seignieur/Dame", "Paladin"},
+	Monk: {"Candidate", "Novice", "Initiate", "Student of Stones", "Student of Waters",
+		"Student of Metals", "Student of Winds", "Student of Fire", "Master"},
+	Priest: {"Aspirant", "Acolyte", "Adept", "Priest/Priestess", "Curate",
+		"Canon/Canoness", "Lama", "Patriarch/Matriarch", "High Priest/High Priestess"},
+	Rogue: {"Footpad", "Cutpurse", "Rogue", "Pilferer", "Robber",
+		"Burglar", "Filcher", "Magsman/Magswoman", "Thief"},
+	Ranger: {"Tenderfoot", "Lookout", "Trailblazer", "Reconnoiterer/Reconnoiteress", "Scout",
+		"Arbalester", "Archer", "Sharpshooter", "Marksman/Markswoman"},
+	Samurai: {"Hatamoto", "Ronin", "Ninja/Kunoichi", "Joshu", "Ryoshu",
+		"Kokushu", "Daimyo", "Kuge", "Shogun"},
+	Tourist: {"Rambler", "Sightseer", "Excursionist", "Peregrinator/Peregrinatrix", "Traveler",
+		"Journeyer", "Voyager", "Explorer", "Adventurer"},
+	Valkyrie: {"Stripling", "Skirmisher", "Fighter", "Man-at-arms/Woman-at-arms", "Warrior",
+		"Swashbuckler", "Hero/Heroine", "Champion", "Lord/Lady"},
+	Wizard: {"Evoker", "Conjurer", "Thaumaturge", "Magician", "Enchanter/Enchantress",
+		"Sorcerer/Sorceress", "Necromancer", "Wizard", "Mage"},
+}
+
+// roleByTitle maps every rank title to its role.
+var roleByTitle = make(map[string]Role)
+
+func init() {
+	for r, titles := range rankTitles {
+		for _, t := range titles {
+			for _, g := range strings.Split(t, "/") {
+				roleByTitle[g] = r
+			}
+		}
+	}
+}
+
+// RoleOf returns the role whose rank title is title. ok is false if no role
+// has that title, as when the status line shows the monster the player is
+// polymorphed into.
+func RoleOf(title string) (r Role, ok bool) {
+	r, ok = roleByTitle[title]
+	return r, ok
+}
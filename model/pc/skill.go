@@ -0,0 +1,14 @@
+package pc
+
+// Skill is the player's level of skill with a weapon or a school of spells.
+type Skill int
+
+// The skill levels. A Restricted skill can't be advanced, and is treated as
+// Unskilled.
+const (
+	Restricted Skill = iota
+	Unskilled
+	Basic
+	Skilled
+	Expert
+)
@@ -0,0 +1,184 @@
+package pc
+
+import (
+	"math"
+
+	"github.com/jaguilar/nh/model/anatomy"
+	"github.com/jaguilar/nh/model/item"
+)
+
+// Spell is a spell the player knows, as the spell menu lists it.
+type Spell struct {
+	// Letter is the spell's letter in the menu.
+	Letter rune
+
+	// Name is the spell's name: "force bolt".
+	Name  string
+	Level int
+	item.School
+
+	// Forgotten is set for spells the player has forgotten. Nethack marks
+	// them with a "*" after their level.
+	Forgotten bool
+
+	// Fail is the percent chance of failure that nethack shows.
+	Fail int
+}
+
+// caster is how good a role is at casting spells, as in nethack's role.c.
+type caster struct {
+	// base is the role's base spellcasting penalty.
+	base int
+
+	// emergency is added to the penalty for the emergency spells, like
+	// healing.
+	emergency int
+
+	// shield is the penalty for wearing a shield, and armor for metallic
+	// body armor.
+	shield, armor int
+
+	// wis is set if the role casts with Wis rather than Int.
+	wis bool
+
+	// special is the role's special spell, which it gets a bonus of bonus
+	// to.
+	special string
+	bonus   int
+}
+
+var casters = map[Role]caster{
+	Archeologist: {5, 0, 2, 10, false, "magic mapping", -4},
+	Barbarian:    {14, 0, 0, 8, false, "haste self", -4},
+	Caveman:      {12, 0, 1, 8, false, "dig", -4},
+	Healer:       {3, -3, 2, 10, true, "cure blindness", -4},
+	Knight:       {8, -2, 0, 9, true, "turn undead", -4},
+	Monk:         {8, -2, 2, 20, true, "restore ability", -4},
+	Priest:       {3, -2, 2, 10, true, "remove curse", -4},
+	Rogue:        {8, 0, 1, 9, false, "detect treasure", -4},
+	Ranger:       {9, 2, 1, 10, false, "invisibility", -4},
+	Samurai:      {10, 0, 0, 8, false, "clairvoyance", -4},
+	Tourist:      {5, 1, 2, 10, false, "charm monster", -4},
+	Valkyrie:     {10, -2, 0, 9, true, "cone of cold", -4},
+	Wizard:       {1, 0, 3, 10, false, "magic missile", -4},
+}
+
+// emergencySpells are the spells that get a role's emergency penalty.
+var emergencySpells = map[string]bool{
+	"healing": true, "extra healing": true, "cure blindness": true,
+	"cure sickness": true, "restore ability": true, "remove curse": true,
+}
+
+// The penalties for metallic helmets, gloves and boots, whatever the role.
+const (
+	helmetPenalty = 4
+	glovesPenalty = 6
+	bootsPenalty  = 2
+)
+
+// SpellFailure returns the percent chance that the player fails to cast s,
+// the way nethack's percent_success works it out from their role, stats,
+// level and armor. skill is their skill in s's school. It returns -1 if we
+// don't know the player's role.
+//
+// A bot can compare the result with and without some armor worn to decide
+// whether to take it off first.
+func (p *Player) SpellFailure(s Spell, skill Skill) int {
+	c, ok := casters[p.Role]
+	if !ok {
+		return -1
+	}
+
+	penalty := c.base
+	var body, cloak, shield *item.Item
+	for _, i := range p.Equip {
+		if i == nil || i.Class.Category != item.Armor || len(i.Class.Slots) == 0 {
+			continue
+		}
+		metal := item.HindersSpellcasting(i.Class)
+		switch i.Class.Slots[0] {
+		case anatomy.Torso:
+			body = i
+		case anatomy.TorsoOver:
+			cloak = i
+		case anatomy.Hand:
+			shield = i
+		case anatomy.Head:
+			if metal && i.Class.Name != "helm of brilliance" {
+				penalty += helmetPenalty
+			}
+		case anatomy.Arms:
+			if metal {
+				penalty += glovesPenalty
+			}
+		case anatomy.Feet:
+			if metal {
+				penalty += bootsPenalty
+			}
+		}
+	}
+	robe := cloak != nil && cloak.Class.Name == "robe"
+	switch {
+	case body != nil && item.HindersSpellcasting(body.Class) && robe:
+		penalty += c.armor / 2
+	case body != nil && item.HindersSpellcasting(body.Class):
+		penalty += c.armor
+	case robe:
+		penalty -= c.armor
+	}
+	if shield != nil {
+		penalty += c.shield
+	}
+	if s.Name == c.special {
+		penalty += c.bonus
+	}
+	if emergencySpells[s.Name] {
+		penalty += c.emergency
+	}
+	if penalty > 20 {
+		penalty = 20
+	}
+
+	stat := p.Int
+	if c.wis {
+		stat = p.Wis
+	}
+	chance := 11 * stat / 2
+
+	sk := int(skill) - 1
+	if skill < Unskilled {
+		sk = 0
+	}
+	difficulty := (s.Level-1)*4 - (sk*6 + p.XL/3 + 1)
+	if difficulty > 0 {
+		chance -= int(math.Sqrt(float64(900*difficulty + 2000)))
+	} else if learning := 15 * -difficulty / s.Level; learning > 20 {
+		chance += 20
+	} else {
+		chance += learning
+	}
+	chance = clamp(chance, 0, 120)
+
+	// Any shield heavier than a small shield, which weighs 30, makes
+	// casting awkward.
+	if shield != nil && shield.Class.Weight > 30 {
+		if s.Name == c.special {
+			chance /= 2
+		} else {
+			chance /= 4
+		}
+	}
+
+	chance = chance*(20-penalty)/15 - penalty
+	return 100 - clamp(chance, 0, 100)
+}
+
+func clamp(x, min, max int) int {
+	switch {
+	case x < min:
+		return min
+	case x > max:
+		return max
+	}
+	return x
+}
@@ -0,0 +1,53 @@
+package pc
+
+import (
+	"testing"
+
+	"github.com/jaguilar/nh/model/item"
+	"github.com/stretchr/testify/assert"
+)
+
+func wizard(t *testing.T, equip ...string) *Player {
+	p := &Player{Role: Wizard, Int: 18, XL: 1}
+	r := item.NewRegistry()
+	for _, e := range equip {
+		i, err := r.Parse(e)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p.Equip = append(p.Equip, i)
+	}
+	return p
+}
+
+func TestSpellFailure(t *testing.T) {
+	forceBolt := Spell{Name: "force bolt", Level: 1, School: item.Attack}
+	magicMissile := Spell{Name: "magic missile", Level: 2, School: item.Attack}
+
+	// A starting wizard always casts force bolt.
+	assert.Equal(t, 0, wizard(t).SpellFailure(forceBolt, Basic))
+	assert.Equal(t, 0, wizard(t).SpellFailure(magicMissile, Basic))
+
+	// A chance of 99 for Int, and 20 for learning, less a penalty of 1,
+	// and 10 for metallic armor, makes 119 * 9 / 15 - 11.
+	plate := "a - a +0 plate mail (being worn)"
+	assert.Equal(t, 40, wizard(t, plate).SpellFailure(forceBolt, Basic))
+
+	// A robe halves the penalty for metallic armor.
+	robe := "b - a +0 robe (being worn)"
+	assert.Equal(t, 0, wizard(t, plate, robe).SpellFailure(forceBolt, Basic))
+
+	// A large shield quarters the chance.
+	shield := "c - a +0 large shield (being worn)"
+	assert.Equal(t, 100, wizard(t, plate, shield).SpellFailure(forceBolt, Basic))
+	assert.Equal(t, 74, wizard(t, shield).SpellFailure(forceBolt, Basic))
+
+	// The helm of brilliance doesn't count as a metallic helmet.
+	assert.Equal(t, 40, wizard(t, plate, "d - a +0 helm of brilliance (being worn)").SpellFailure(forceBolt, Basic))
+	assert.Equal(t, 76, wizard(t, plate, "d - a +0 dwarvish iron helm (being worn)").SpellFailure(forceBolt, Basic))
+
+	// A level 7 spell is out of a starting wizard's reach.
+	assert.Equal(t, 100, wizard(t).SpellFailure(Spell{Name: "finger of death", Level: 7}, Basic))
+
+	assert.Equal(t, -1, (&Player{}).SpellFailure(forceBolt, Basic))
+}
@@ -0,0 +1,53 @@
+package model
+
+import (
+	"github.com/jaguilar/nh/model/event"
+	"github.com/jaguilar/nh/model/item"
+	"github.com/jaguilar/nh/model/shop"
+)
+
+// identifyByPrice narrows down what the items shopkeepers quoted prices for
+// this turn might be.
+//
+// The price a shopkeeper offers for an item we're selling is for the whole
+// stack, and the message doesn't say how many there are. We only use it if
+// the item is in our Pack, so that we know.
+func (g *Game) identifyByPrice() {
+	c := shop.CustomerOf(&g.Player)
+	for _, m := range g.turn {
+		var o shop.Offer
+		switch e := event.Parse(m).(type) {
+		case event.BuyOffer:
+			i, err := g.Registry.Parse(e.Item)
+			if err != nil {
+				continue
+			}
+			o = shop.Offer{Item: i, Price: e.Price, Angry: e.Angry}
+		case event.SellOffer:
+			i, err := g.Registry.Parse(e.Item)
+			if err != nil {
+				continue
+			}
+			p := g.packItem(i.Class)
+			if p == nil {
+				continue
+			}
+			o = shop.Offer{Item: p, Price: e.Price, Sell: true, Quantity: p.Stack}
+		default:
+			continue
+		}
+		// Offers for items whose appearance isn't shuffled, or that don't
+		// fit what we know, are no use to us.
+		shop.Restrict(g.Registry, c, o)
+	}
+}
+
+// packItem returns the item in our Pack of class c, or nil if there's none.
+func (g *Game) packItem(c *item.Class) *item.Item {
+	for _, i := range g.Pack {
+		if i.Class == c {
+			return i
+		}
+	}
+	return nil
+}
@@ -9,13 +9,14 @@ import (
 )
 
 // RFunc represents a general random calculation from the game. You can get the
-// min, max, and average value from it, or you can execute it to get one random
-// sample. All nethack random functions have an unskewed probability
-// distribution (we think?), so the average can be found by summing min and max
-// and dividing by two.
+// min and max value from it, its exact probability distribution, or you can
+// execute it to get one random sample. Not every distribution is symmetric
+// (the sum of a die and a d20 bonus that only applies sometimes isn't), so
+// use Dist, not Bound, to find the average.
 type RFunc interface {
 	Bound() (int, int) // Return the inclusive lower and upper bounds of the function.
 	Do() int           // Do executes the random function once and returns the result.
+	Dist() Dist        // Dist returns the probability distribution of Do's results.
 }
 
 var diceRe = regexp.MustCompile(`^(\d*?)(d)?(\d+)$`)
@@ -25,59 +26,91 @@ var diceRe = regexp.MustCompile(`^(\d*?)(d)?(\d+)$`)
 //
 // The grammar is:
 //
-// DiceExpression       = Expression | Expression "+" DiceExpression .
+// DiceExpression       = Term | Term ( "+" | "-" ) DiceExpression .
+// Term                 = Expression [ "*" int_lit ] .
 // Expression           = Dice | Constant .
 // Dice                 = [ int_lit ] "d" int_lit
 // Constant             = int_lit
 //
-// Any amount of white space between each expression is ignored. Whitespace
-// inside an expression is not allowed. For example:
+// Any amount of white space between each term is ignored. Whitespace
+// inside a term is not allowed. For example:
 //
 // - "1d2   +1" -- ok
 // - "1d2+1"    -- ok
+// - "2d4-1"    -- ok
 // - "1 d2+1"   -- error
 //
-// Dice "xdy" means "roll a y sided die x times"
+// Dice "xdy" means "roll a y sided die x times". A term "d20*2" is one roll
+// of a d20, doubled.
 func Dice(s string) (RFunc, error) {
-	parts := strings.Split(s, "+")
 	var expressions []RFunc
 
-	for _, p := range parts {
-		match := diceRe.FindStringSubmatch(p)
-		if match == nil {
-			return nil, fmt.Errorf("can't parse as dice expression: %s", s)
+	sign, start := 1, 0
+	for k := 0; k <= len(s); k++ {
+		if k < len(s) && s[k] != '+' && s[k] != '-' {
+			continue
 		}
+		t, err := term(strings.TrimSpace(s[start:k]))
+		if err != nil {
+			return nil, fmt.Errorf("can't parse as dice expression: %s: %v", s, err)
+		}
+		if sign < 0 {
+			t = Times(t, -1)
+		}
+		expressions = append(expressions, t)
+		if k < len(s) && s[k] == '-' {
+			sign = -1
+		} else {
+			sign = 1
+		}
+		start = k + 1
+	}
 
-		isDice := match[2] != ""
+	return joined(expressions), nil
+}
 
-		if isDice {
-			if match[3] == "" {
-				return nil, fmt.Errorf("each dice must have a sides count: %s", s)
-			}
+// term parses a Term of a dice expression.
+func term(s string) (RFunc, error) {
+	k := 1
+	if i := strings.Index(s, "*"); i >= 0 {
+		var err error
+		if k, err = strconv.Atoi(s[i+1:]); err != nil {
+			return nil, err
+		}
+		s = s[:i]
+	}
 
-			d := dice{n: 1}
-			var err error
-			if match[1] != "" {
-				d.n, err = strconv.Atoi(match[1])
-				if err != nil {
-					return nil, err
-				}
-			}
-			d.sides, err = strconv.Atoi(match[3])
-			if err != nil {
-				return nil, err
-			}
-			expressions = append(expressions, RFunc(d))
-		} else { // Constant expression.
-			c, err := strconv.Atoi(match[3])
+	match := diceRe.FindStringSubmatch(s)
+	if match == nil {
+		return nil, fmt.Errorf("bad term %q", s)
+	}
+
+	var r RFunc
+	if match[2] != "" {
+		d := dice{n: 1}
+		var err error
+		if match[1] != "" {
+			d.n, err = strconv.Atoi(match[1])
 			if err != nil {
 				return nil, err
 			}
-			expressions = append(expressions, RFunc(constant(c)))
 		}
+		d.sides, err = strconv.Atoi(match[3])
+		if err != nil {
+			return nil, err
+		}
+		r = d
+	} else { // Constant expression.
+		c, err := strconv.Atoi(match[3])
+		if err != nil {
+			return nil, err
+		}
+		r = constant(c)
 	}
-
-	return joined(expressions), nil
+	if k != 1 {
+		r = Times(r, k)
+	}
+	return r, nil
 }
 
 // DiceMust is like Dice, but panics if there is an error.
@@ -96,6 +129,14 @@ type dice struct {
 func (d dice) Bound() (int, int) {
 	return d.n * 1, d.n * d.sides
 }
+func (d dice) Dist() Dist {
+	o := point(0)
+	for i := 0; i < d.n; i++ {
+		o = convolve(o, uniform(d.sides))
+	}
+	return o
+}
+
 func (d dice) Do() int {
 	var o int
 	for i := 0; i < d.n; i++ {
@@ -108,6 +149,7 @@ type constant int
 
 func (c constant) Bound() (int, int) { return int(c), int(c) }
 func (c constant) Do() int           { return int(c) }
+func (c constant) Dist() Dist        { return point(int(c)) }
 
 type joined []RFunc
 
@@ -121,6 +163,14 @@ func (j joined) Bound() (int, int) {
 	return min, max
 }
 
+func (j joined) Dist() Dist {
+	o := point(0)
+	for _, r := range j {
+		o = convolve(o, r.Dist())
+	}
+	return o
+}
+
 func (j joined) Do() int {
 	var d int
 	for _, r := range j {
@@ -133,3 +183,93 @@ func (j joined) Do() int {
 func Sum(rr ...RFunc) RFunc {
 	return joined(rr)
 }
+
+// Const returns an RFunc that always returns c. Unlike a Dice constant, c
+// may be negative.
+func Const(c int) RFunc {
+	return constant(c)
+}
+
+type atLeast struct {
+	r   RFunc
+	min int
+}
+
+func (a atLeast) Bound() (int, int) {
+	min, max := a.r.Bound()
+	if min < a.min {
+		min = a.min
+	}
+	if max < a.min {
+		max = a.min
+	}
+	return min, max
+}
+
+func (a atLeast) Dist() Dist {
+	return mapDist(a.r.Dist(), func(x int) int {
+		if x < a.min {
+			return a.min
+		}
+		return x
+	})
+}
+
+func (a atLeast) Do() int {
+	if d := a.r.Do(); d > a.min {
+		return d
+	}
+	return a.min
+}
+
+// AtLeast returns an RFunc that models r, but returns min whenever r would
+// be less, the way nethack keeps damage from going below 1.
+func AtLeast(r RFunc, min int) RFunc {
+	return atLeast{r, min}
+}
+
+type times struct {
+	r RFunc
+	k int
+}
+
+func (t times) Bound() (int, int) {
+	min, max := t.r.Bound()
+	if t.k < 0 {
+		min, max = max, min
+	}
+	return t.k * min, t.k * max
+}
+
+func (t times) Do() int { return t.k * t.r.Do() }
+
+func (t times) Dist() Dist {
+	return mapDist(t.r.Dist(), func(x int) int { return t.k * x })
+}
+
+// Times returns an RFunc that models one result of r multiplied by k. It's
+// not the sum of k results of r: Times(d4, 2) is always even.
+func Times(r RFunc, k int) RFunc {
+	return times{r, k}
+}
+
+type mapped struct {
+	r RFunc
+	f func(int) int
+}
+
+func (m mapped) Bound() (int, int) {
+	d := m.Dist()
+	return d.Min, d.Max()
+}
+
+func (m mapped) Do() int { return m.f(m.r.Do()) }
+
+func (m mapped) Dist() Dist { return mapDist(m.r.Dist(), m.f) }
+
+// Map returns an RFunc that models f of a result of r, for the adjustments
+// nethack makes that depend on the roll, like only taking erosion off
+// damage that isn't already 0.
+func Map(r RFunc, f func(int) int) RFunc {
+	return mapped{r, f}
+}
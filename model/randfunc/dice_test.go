@@ -46,3 +46,36 @@ func TestSum(t *testing.T) {
 	assert.Equal(t, 7, max)
 	assert.InEpsilon(t, 5, convergentAvg(s), near)
 }
+
+func TestAtLeastAndTimes(t *testing.T) {
+	a := AtLeast(Sum(DiceMust("d4"), Const(-2)), 1)
+	min, max := a.Bound()
+	assert.Equal(t, 1, min)
+	assert.Equal(t, 2, max)
+	assert.InEpsilon(t, 1.25, convergentAvg(a), near)
+
+	d := Times(DiceMust("d4"), 2)
+	min, max = d.Bound()
+	assert.Equal(t, 2, min)
+	assert.Equal(t, 8, max)
+	for i := 0; i < 100; i++ {
+		assert.Equal(t, 0, d.Do()%2)
+	}
+}
+
+func TestMap(t *testing.T) {
+	// Take 2 off a d4, but only if it isn't 1.
+	m := Map(DiceMust("d4"), func(x int) int {
+		if x > 1 {
+			x -= 2
+		}
+		return x
+	})
+	min, max := m.Bound()
+	assert.Equal(t, 0, min)
+	assert.Equal(t, 2, max)
+	assert.Equal(t, []float64{0.25, 0.5, 0.25}, m.Dist().P)
+	for i := 0; i < 100; i++ {
+		assert.True(t, m.Do() <= 2)
+	}
+}
@@ -0,0 +1,115 @@
+package randfunc
+
+import "math"
+
+// Dist is the exact probability distribution of an RFunc's results.
+type Dist struct {
+	// Min is the smallest result. P[k] is the probability of the result
+	// Min+k. The probabilities sum to 1.
+	Min int
+	P   []float64
+}
+
+// Max returns the largest result.
+func (d Dist) Max() int {
+	return d.Min + len(d.P) - 1
+}
+
+// Prob returns the probability that the result is x.
+func (d Dist) Prob(x int) float64 {
+	if x < d.Min || x > d.Max() {
+		return 0
+	}
+	return d.P[x-d.Min]
+}
+
+// AtLeast returns the probability that the result is x or more.
+func (d Dist) AtLeast(x int) float64 {
+	if x < d.Min {
+		x = d.Min
+	}
+	var p float64
+	for k := x - d.Min; k < len(d.P); k++ {
+		p += d.P[k]
+	}
+	return p
+}
+
+// Mean returns the expected result.
+func (d Dist) Mean() float64 {
+	var m float64
+	for k, p := range d.P {
+		m += float64(d.Min+k) * p
+	}
+	return m
+}
+
+// Variance returns the variance of the result.
+func (d Dist) Variance() float64 {
+	m := d.Mean()
+	var v float64
+	for k, p := range d.P {
+		x := float64(d.Min+k) - m
+		v += x * x * p
+	}
+	return v
+}
+
+// Quantile returns the smallest result x for which the probability that the
+// result is x or less is at least q. Quantile(0.5) is the median.
+func (d Dist) Quantile(q float64) int {
+	var p float64
+	for k := range d.P {
+		p += d.P[k]
+		// Leave room for rounding error in the sum.
+		if p >= q-1e-12 {
+			return d.Min + k
+		}
+	}
+	return d.Max()
+}
+
+// point returns the distribution of a result that's always x.
+func point(x int) Dist {
+	return Dist{Min: x, P: []float64{1}}
+}
+
+// uniform returns the distribution of a roll of a die with sides sides.
+func uniform(sides int) Dist {
+	d := Dist{Min: 1, P: make([]float64, sides)}
+	for k := range d.P {
+		d.P[k] = 1 / float64(sides)
+	}
+	return d
+}
+
+// convolve returns the distribution of the sum of independent results from
+// a and b.
+func convolve(a, b Dist) Dist {
+	d := Dist{Min: a.Min + b.Min, P: make([]float64, len(a.P)+len(b.P)-1)}
+	for i, p := range a.P {
+		for j, q := range b.P {
+			d.P[i+j] += p * q
+		}
+	}
+	return d
+}
+
+// mapDist returns the distribution of f of a result from d.
+func mapDist(d Dist, f func(int) int) Dist {
+	min, max := math.MaxInt32, math.MinInt32
+	for k := range d.P {
+		y := f(d.Min + k)
+		if y < min {
+			min = y
+		}
+		if y > max {
+			max = y
+		}
+	}
+	o := Dist{Min: min, P: make([]float64, max-min+1)}
+	for k, p := range d.P {
+		o.P[f(d.Min+k)-min] += p
+	}
+	return o
+}
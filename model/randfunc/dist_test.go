@@ -0,0 +1,54 @@
+package randfunc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDist(t *testing.T) {
+	d := DiceMust("2d6").Dist()
+	assert.Equal(t, 2, d.Min)
+	assert.Equal(t, 12, d.Max())
+	assert.InDelta(t, 1.0/36, d.Prob(2), 1e-12)
+	assert.InDelta(t, 6.0/36, d.Prob(7), 1e-12)
+	assert.Equal(t, 0.0, d.Prob(13))
+	assert.InDelta(t, 7, d.Mean(), 1e-12)
+	assert.InDelta(t, 35.0/6, d.Variance(), 1e-12)
+	assert.InDelta(t, 21.0/36, d.AtLeast(7), 1e-12)
+	assert.InDelta(t, 1, d.AtLeast(-5), 1e-12)
+	assert.Equal(t, 0.0, d.AtLeast(13))
+	assert.Equal(t, 7, d.Quantile(0.5))
+	assert.Equal(t, 2, d.Quantile(0))
+	assert.Equal(t, 12, d.Quantile(1))
+
+	c := Const(-3).Dist()
+	assert.Equal(t, -3, c.Min)
+	assert.Equal(t, 0.0, c.Variance())
+}
+
+func TestDistSkewed(t *testing.T) {
+	// d4 - 2, at least 1, is 1 unless the d4 rolls a 4.
+	d := AtLeast(Sum(DiceMust("d4"), Const(-2)), 1).Dist()
+	assert.Equal(t, []float64{0.75, 0.25}, d.P)
+	assert.InDelta(t, 1.25, d.Mean(), 1e-12)
+
+	d = Times(DiceMust("d3"), 2).Dist()
+	assert.Equal(t, 2, d.Min)
+	assert.InDelta(t, 0, d.Prob(3), 1e-12)
+	assert.InDelta(t, 1.0/3, d.Prob(6), 1e-12)
+	assert.InDelta(t, 4, d.Mean(), 1e-12)
+
+	d = Times(DiceMust("d3"), -1).Dist()
+	assert.Equal(t, -3, d.Min)
+	assert.InDelta(t, -2, d.Mean(), 1e-12)
+
+	// Each RFunc's distribution agrees with its bounds.
+	for _, r := range []RFunc{DiceMust("d8+d4"), Sum(), Times(DiceMust("2d4"), 3), AtLeast(DiceMust("d6"), 4)} {
+		min, max := r.Bound()
+		d := r.Dist()
+		assert.Equal(t, min, d.Min)
+		assert.Equal(t, max, d.Max())
+		assert.InDelta(t, convergentAvg(r), d.Mean(), 0.5)
+	}
+}
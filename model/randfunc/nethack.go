@@ -0,0 +1,174 @@
+package randfunc
+
+import "math/rand"
+
+// The functions here are nethack's own random number generators, from
+// rnd.c in 3.4.3. Their distributions are worked out from the C source.
+
+// Rn2 returns an RFunc that models nethack's rn2(x): 0 to x-1.
+func Rn2(x int) RFunc {
+	return Sum(dice{1, x}, Const(-1))
+}
+
+// Rnd returns an RFunc that models nethack's rnd(x): 1 to x.
+func Rnd(x int) RFunc {
+	return dice{1, x}
+}
+
+// D returns an RFunc that models nethack's d(n, x): the sum of n rolls of an
+// x-sided die.
+func D(n, x int) RFunc {
+	return dice{n, x}
+}
+
+type rnl struct {
+	x, luck int
+}
+
+// Rnl returns an RFunc that models nethack's rnl(x), which is rn2(x)
+// adjusted by the player's luck: usually lower with good luck and higher
+// with bad. Lower is better for the things it's used for, like prayer and
+// untrapping.
+func Rnl(x, luck int) RFunc {
+	return rnl{x, luck}
+}
+
+// A roll can always go unadjusted, so luck doesn't narrow the bounds.
+func (r rnl) Bound() (int, int) { return 0, r.x - 1 }
+
+// adjust returns what an adjusted roll of i becomes.
+func (r rnl) adjust(i int) int {
+	adj := r.luck
+	if r.x <= 15 && r.luck >= -5 {
+		adj = r.luck / 3
+	}
+	i -= adj
+	switch {
+	case i < 0:
+		return 0
+	case i >= r.x:
+		return r.x - 1
+	}
+	return i
+}
+
+func (r rnl) Do() int {
+	i := rand.Intn(r.x)
+	if r.luck != 0 && rand.Intn(50-r.luck) != 0 {
+		i = r.adjust(i)
+	}
+	return i
+}
+
+func (r rnl) Dist() Dist {
+	d := Dist{Min: 0, P: make([]float64, r.x)}
+	// A roll is adjusted unless rn2(50 - luck) comes up 0.
+	pAdjust := 0.0
+	if r.luck != 0 {
+		pAdjust = 1 - 1/float64(50-r.luck)
+	}
+	for i := 0; i < r.x; i++ {
+		p := 1 / float64(r.x)
+		d.P[i] += p * (1 - pAdjust)
+		d.P[r.adjust(i)] += p * pAdjust
+	}
+	return d
+}
+
+type rne struct {
+	x, max int
+}
+
+// Rne returns an RFunc that models nethack's rne(x) for a player of
+// experience level xl: 1, plus 1 more each time a 1 in x chance comes up,
+// up to a cap of 5, or a third of xl from level 15 on. It's used for the
+// enchantment of generated items, among other things.
+func Rne(x, xl int) RFunc {
+	max := 5
+	if xl >= 15 {
+		max = xl / 3
+	}
+	return rne{x, max}
+}
+
+func (r rne) Bound() (int, int) { return 1, r.max }
+
+func (r rne) Do() int {
+	n := 1
+	for n < r.max && rand.Intn(r.x) == 0 {
+		n++
+	}
+	return n
+}
+
+func (r rne) Dist() Dist {
+	d := Dist{Min: 1, P: make([]float64, r.max)}
+	p := 1.0 // The chance of getting this far.
+	for k := 1; k < r.max; k++ {
+		d.P[k-1] = p * (1 - 1/float64(r.x))
+		p /= float64(r.x)
+	}
+	d.P[r.max-1] = p
+	return d
+}
+
+type rnz struct {
+	i   int
+	rne rne
+}
+
+// Rnz returns an RFunc that models nethack's rnz(i) for a player of
+// experience level xl. It's i, multiplied or divided (with equal chances)
+// by a factor with a long tail. It's used for prayer timeouts and the
+// duration of some intrinsics.
+func Rnz(i, xl int) RFunc {
+	return rnz{i, Rne(4, xl).(rne)}
+}
+
+// rnz works out rnz from the rolls it makes: tmp is 1000 + rn2(1000),
+// times rne(4).
+func (r rnz) rnz(tmp int, up bool) int {
+	x := r.i
+	if up {
+		return x * tmp / 1000
+	}
+	return x * 1000 / tmp
+}
+
+func (r rnz) Bound() (int, int) {
+	// The extremes come from the smallest or biggest factor, one way or
+	// the other.
+	min, max := r.i, r.i
+	for _, tmp := range []int{1000, 1999 * r.rne.max} {
+		for _, up := range []bool{false, true} {
+			x := r.rnz(tmp, up)
+			if x < min {
+				min = x
+			}
+			if x > max {
+				max = x
+			}
+		}
+	}
+	return min, max
+}
+
+func (r rnz) Do() int {
+	tmp := (1000 + rand.Intn(1000)) * r.rne.Do()
+	return r.rnz(tmp, rand.Intn(2) != 0)
+}
+
+func (r rnz) Dist() Dist {
+	min, max := r.Bound()
+	d := Dist{Min: min, P: make([]float64, max-min+1)}
+	e := r.rne.Dist()
+	for k, pe := range e.P {
+		for n := 1000; n < 2000; n++ {
+			tmp := n * (e.Min + k)
+			p := pe / 1000 / 2
+			d.P[r.rnz(tmp, true)-min] += p
+			d.P[r.rnz(tmp, false)-min] += p
+		}
+	}
+	return d
+}
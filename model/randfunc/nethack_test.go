@@ -0,0 +1,99 @@
+package randfunc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRn2Rnd(t *testing.T) {
+	d := Rn2(4).Dist()
+	assert.Equal(t, 0, d.Min)
+	assert.Equal(t, 3, d.Max())
+	assert.InDelta(t, 1.5, d.Mean(), 1e-12)
+
+	min, max := Rnd(20).Bound()
+	assert.Equal(t, 1, min)
+	assert.Equal(t, 20, max)
+	assert.InDelta(t, 7, D(2, 6).Dist().Mean(), 1e-12)
+}
+
+func TestRnl(t *testing.T) {
+	// Without luck, rnl is rn2.
+	assert.Equal(t, Rn2(20).Dist(), Rnl(20, 0).Dist())
+
+	// With Luck 10, a roll of rnl(20) is lowered by 10, unless a 1 in 40
+	// chance comes up.
+	d := Rnl(20, 10).Dist()
+	assert.InDelta(t, 1, d.AtLeast(0), 1e-12)
+	assert.InDelta(t, 11.0/20*39/40+1.0/20/40, d.Prob(0), 1e-12)
+	assert.InDelta(t, 1.0/20/40, d.Prob(19), 1e-12)
+
+	// For small x, only a third of luck counts.
+	d = Rnl(10, 3).Dist()
+	assert.InDelta(t, 2.0/10*46/47+1.0/10/47, d.Prob(0), 1e-12)
+
+	// Bad luck makes rolls higher.
+	assert.True(t, Rnl(20, -10).Dist().Mean() > 15)
+	assert.InEpsilon(t, Rnl(20, -10).Dist().Mean(), convergentAvg(Rnl(20, -10)), near)
+}
+
+func TestRne(t *testing.T) {
+	d := Rne(3, 1).Dist()
+	assert.Equal(t, 1, d.Min)
+	assert.Equal(t, 5, d.Max())
+	assert.InDelta(t, 2.0/3, d.Prob(1), 1e-12)
+	assert.InDelta(t, 2.0/9, d.Prob(2), 1e-12)
+	assert.InDelta(t, 1.0/81, d.Prob(5), 1e-12)
+
+	// From level 15, the cap is a third of the player's level.
+	assert.Equal(t, 10, Rne(3, 30).Dist().Max())
+	assert.InEpsilon(t, d.Mean(), convergentAvg(Rne(3, 1)), near)
+}
+
+func TestRnz(t *testing.T) {
+	r := Rnz(350, 1)
+	min, max := r.Bound()
+	assert.Equal(t, 350*1000/(1999*5), min)
+	assert.Equal(t, 350*1999*5/1000, max)
+
+	d := r.Dist()
+	assert.Equal(t, min, d.Min)
+	assert.Equal(t, max, d.Max())
+	assert.InDelta(t, 1, d.AtLeast(min), 1e-9)
+	// Half the time it's divided, so at most 350.
+	assert.InDelta(t, 0.5, 1-d.AtLeast(351), 0.01)
+	assert.InEpsilon(t, d.Mean(), convergentAvg(r), 0.2)
+	for i := 0; i < 100; i++ {
+		x := r.Do()
+		assert.True(t, x >= min && x <= max)
+	}
+}
+
+func TestDiceArithmetic(t *testing.T) {
+	for _, tc := range []struct {
+		string
+		min, max int
+		mean     float64
+	}{
+		{"2d4-1", 1, 7, 4},
+		{"d20*2", 2, 40, 21},
+		{"d6 - d4 + 1", -2, 6, 2},
+		{"3*2+d2", 7, 8, 7.5},
+	} {
+		r, err := Dice(tc.string)
+		if !assert.Nil(t, err, tc.string) {
+			continue
+		}
+		min, max := r.Bound()
+		assert.Equal(t, tc.min, min, tc.string)
+		assert.Equal(t, tc.max, max, tc.string)
+		assert.InDelta(t, tc.mean, r.Dist().Mean(), 1e-12, tc.string)
+	}
+	assert.Equal(t, 0.0, DiceMust("d20*2").Dist().Prob(3))
+
+	for _, s := range []string{"2d4-", "d20*", "d20*x", "2*d4", "-1"} {
+		_, err := Dice(s)
+		assert.NotNil(t, err, s)
+	}
+}
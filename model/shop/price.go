@@ -0,0 +1,246 @@
+/*
+Package shop works out what items are from what shopkeepers charge for them.
+
+Every item class has a base price, and many shuffled classes have distinctive
+ones: of the rings, only the ring of conflict costs 300. A shopkeeper's price
+is the base price adjusted for the customer. BuyPrices and SellPrices work
+that adjustment forwards, the way nethack's get_cost and set_cost do.
+Restrict uses them to drop the candidates for an appearance whose base price
+couldn't have given the price we were quoted.
+*/
+package shop
+
+import (
+	"fmt"
+
+	"github.com/jaguilar/nh/model/anatomy"
+	"github.com/jaguilar/nh/model/item"
+	"github.com/jaguilar/nh/model/pc"
+)
+
+// suckerLevel is the experience level from which a Tourist stops being
+// overcharged.
+const suckerLevel = 15
+
+// maxUnknownEnchantment is the highest enchantment we'll assume a weapon or
+// armor might have, when we don't know. Each point adds 10 to its price.
+const maxUnknownEnchantment = 7
+
+// Customer is what a shopkeeper takes into account when pricing an item.
+type Customer struct {
+	Cha int
+
+	// Sucker is set if the shopkeeper takes us for a mark: we're wearing a
+	// dunce cap, or we're a Tourist below XL 15, or our shirt is showing.
+	// Suckers pay a third more, and get a third rather than half of the
+	// price when they sell.
+	Sucker bool
+
+	// Hunger makes food dearer. Hungry customers pay twice the price, Weak
+	// ones three times, and so on.
+	pc.Hunger
+}
+
+// CustomerOf returns the Customer that p is to a shopkeeper. Only the items
+// in p's Pack that are being worn are looked at. A conical hat we haven't
+// identified is taken to be a dunce cap, which is the likelier of the two.
+func CustomerOf(p *pc.Player) Customer {
+	c := Customer{Cha: p.Cha, Hunger: p.Hunger}
+	if p.Role == pc.Tourist && p.XL < suckerLevel {
+		c.Sucker = true
+	}
+	shirt, covered := false, false
+	for _, i := range p.Pack {
+		if i.Use != item.Worn {
+			continue
+		}
+		switch {
+		case i.Class.Name == "dunce cap",
+			i.Class.Name == "" && i.Class.Appearance == "conical hat":
+			c.Sucker = true
+		}
+		for _, s := range i.Class.Slots {
+			switch s {
+			case anatomy.TorsoUnder:
+				shirt = true
+			case anatomy.Torso, anatomy.TorsoOver:
+				covered = true
+			}
+		}
+	}
+	if shirt && !covered {
+		c.Sucker = true
+	}
+	return c
+}
+
+// scale multiplies price by mul/div the way nethack does, rounding to the
+// nearest whole zorkmid.
+func scale(price, mul, div int) int {
+	price *= mul
+	if div > 1 {
+		price = (price*10/div + 5) / 10
+	}
+	return price
+}
+
+// BuyPrices returns the prices a shopkeeper might ask c for one item with a
+// base price. If the item's class isn't identified, a quarter of items are
+// marked up by another third, so there are two possible prices. An angry
+// shopkeeper adds a third on top of everything.
+func (c Customer) BuyPrices(base int, identified, angry bool) []int {
+	if base == 0 {
+		base = 5
+	}
+	mul, div := 1, 1
+	if c.Sucker {
+		mul, div = mul*4, div*3
+	}
+	switch {
+	case c.Cha > 18:
+		div *= 2
+	case c.Cha == 18:
+		mul, div = mul*2, div*3
+	case c.Cha >= 16:
+		mul, div = mul*3, div*4
+	case c.Cha <= 5:
+		mul *= 2
+	case c.Cha <= 7:
+		mul, div = mul*3, div*2
+	case c.Cha <= 10:
+		mul, div = mul*4, div*3
+	}
+
+	ratios := [][2]int{{mul, div}}
+	if !identified {
+		ratios = append(ratios, [2]int{mul * 4, div * 3})
+	}
+	var prices []int
+	for _, r := range ratios {
+		p := scale(base, r[0], r[1])
+		if p <= 0 {
+			p = 1
+		}
+		if angry {
+			p += (p + 2) / 3
+		}
+		prices = append(prices, p)
+	}
+	return prices
+}
+
+// SellPrices returns what a shopkeeper might offer c for n items with a base
+// price. Shopkeepers pay half the base price, or a third to suckers. If the
+// item's class isn't identified, they may also knock a quarter off that.
+func (c Customer) SellPrices(base, n int, identified bool) []int {
+	total := base * n
+	div := 2
+	if c.Sucker {
+		div = 3
+	}
+	ratios := [][2]int{{1, div}}
+	if !identified && total > 1 {
+		ratios = append(ratios, [2]int{3, div * 4})
+	}
+	var prices []int
+	for _, r := range ratios {
+		p := total
+		if p >= 1 {
+			p = scale(p, r[0], r[1])
+			if p < 1 {
+				p = 1
+			}
+		}
+		prices = append(prices, p)
+	}
+	return prices
+}
+
+// Offer is a price a shopkeeper quoted us for an item.
+type Offer struct {
+	// Item is the item the offer was for. Its Class tells us the appearance,
+	// and its Enhancement, if Known, is taken into account.
+	Item *item.Item
+
+	// Price is the price quoted. For a sale, it's for Quantity items; for a
+	// purchase, it's for one.
+	Price int
+
+	// Sell is set if the shopkeeper offered to buy the item from us.
+	Sell bool
+
+	// Quantity is the number of items a sale is for.
+	Quantity int
+
+	// Angry is set if the shopkeeper is angry with us.
+	Angry bool
+}
+
+// basePrices returns the base prices that an item of class k might have,
+// given what we know about the item. Weapons and armor are worth 10 more
+// for each point of positive enchantment. Food costs more when we're
+// hungry, but only when we buy it.
+func basePrices(k *item.Class, i *item.Item, sell bool, h pc.Hunger) []int {
+	base := k.Price
+	switch k.Category {
+	case item.Weapon, item.Armor:
+		if i.Enhancement.Known {
+			if i.Enhancement.Value > 0 {
+				base += 10 * i.Enhancement.Value
+			}
+			return []int{base}
+		}
+		var bases []int
+		for e := 0; e <= maxUnknownEnchantment; e++ {
+			bases = append(bases, base+10*e)
+		}
+		return bases
+	case item.Comestible:
+		// Our Hunger values from Hungry on are the same as nethack's, which
+		// it uses as the multiplier.
+		if !sell && h >= pc.Hungry {
+			base *= int(h)
+		}
+	}
+	return []int{base}
+}
+
+// Matches returns whether a shopkeeper could have made the offer o to c for
+// an item of class k.
+func (c Customer) Matches(o Offer, k *item.Class) bool {
+	identified := o.Item.Class.Name != ""
+	for _, base := range basePrices(k, o.Item, o.Sell, c.Hunger) {
+		var prices []int
+		if o.Sell {
+			n := o.Quantity
+			if n == 0 {
+				n = 1
+			}
+			prices = c.SellPrices(base, n, identified)
+		} else {
+			prices = c.BuyPrices(base, identified, o.Angry)
+		}
+		for _, p := range prices {
+			if p == o.Price {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Restrict narrows the candidates for the offered item's appearance in r to
+// the classes whose price matches the offer.
+//
+// Gems are refused. Shopkeepers make up prices for worthless glass to fool
+// customers who can't tell it from the real thing, so the price of a gem
+// tells us nothing.
+func Restrict(r *item.Registry, c Customer, o Offer) error {
+	cl := o.Item.Class
+	if cl.Category == item.Gem {
+		return fmt.Errorf("can't identify gems by price: %q", cl.Appearance)
+	}
+	return r.Restrict(cl.Appearance, func(k *item.Class) bool {
+		return c.Matches(o, k)
+	})
+}
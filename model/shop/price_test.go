@@ -0,0 +1,97 @@
+package shop
+
+import (
+	"testing"
+
+	"github.com/jaguilar/nh/model/item"
+	"github.com/jaguilar/nh/model/pc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuyPrices(t *testing.T) {
+	for _, tc := range []struct {
+		c          Customer
+		base       int
+		identified bool
+		angry      bool
+		want       []int
+	}{
+		{Customer{Cha: 11}, 300, true, false, []int{300}},
+		{Customer{Cha: 11}, 300, false, false, []int{300, 400}},
+		{Customer{Cha: 18}, 300, true, false, []int{200}},
+		{Customer{Cha: 19}, 5, true, false, []int{3}},
+		{Customer{Cha: 7}, 100, true, false, []int{150}},
+		{Customer{Cha: 3}, 100, true, false, []int{200}},
+		{Customer{Cha: 10, Sucker: true}, 300, true, false, []int{533}},
+		{Customer{Cha: 11}, 0, true, false, []int{5}},
+		{Customer{Cha: 11}, 20, true, true, []int{27}},
+	} {
+		assert.Equal(t, tc.want, tc.c.BuyPrices(tc.base, tc.identified, tc.angry), "%+v %d", tc.c, tc.base)
+	}
+}
+
+func TestSellPrices(t *testing.T) {
+	c := Customer{Cha: 18}
+	assert.Equal(t, []int{150}, c.SellPrices(300, 1, true))
+	assert.Equal(t, []int{150, 113}, c.SellPrices(300, 1, false))
+	assert.Equal(t, []int{40, 30}, c.SellPrices(20, 4, false))
+	c.Sucker = true
+	assert.Equal(t, []int{100, 75}, c.SellPrices(300, 1, false))
+}
+
+func TestCustomerOf(t *testing.T) {
+	r := item.NewRegistry()
+	worn := func(s string) *item.Item {
+		i, err := r.Parse(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return i
+	}
+
+	p := pc.Player{Role: pc.Tourist, XL: 3, Cha: 16}
+	assert.Equal(t, Customer{Cha: 16, Sucker: true}, CustomerOf(&p))
+	p.XL = 15
+	assert.False(t, CustomerOf(&p).Sucker)
+
+	p = pc.Player{Role: pc.Valkyrie, Hunger: pc.Weak}
+	assert.Equal(t, Customer{Hunger: pc.Weak}, CustomerOf(&p))
+	p.Pack = []*item.Item{worn("a - a Hawaiian shirt (being worn)")}
+	assert.True(t, CustomerOf(&p).Sucker, "shirt showing")
+	p.Pack = append(p.Pack, worn("b - a tattered cape (being worn)"))
+	assert.False(t, CustomerOf(&p).Sucker, "shirt covered")
+	p.Pack = append(p.Pack, worn("c - a conical hat (being worn)"))
+	assert.True(t, CustomerOf(&p).Sucker, "dunce cap")
+}
+
+func TestRestrict(t *testing.T) {
+	r := item.NewRegistry()
+	ring, err := r.Parse("wooden ring")
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	// Only the 300zm rings cost 300 with or without the surcharge. (No ring
+	// costs 225.)
+	c := Customer{Cha: 12}
+	assert.Nil(t, Restrict(r, c, Offer{Item: ring, Price: 300}))
+	assert.Len(t, r.Candidates("wooden ring"), 4)
+
+	// Selling one for 150 leaves the same four.
+	assert.Nil(t, Restrict(r, c, Offer{Item: ring, Price: 150, Sell: true, Quantity: 1}))
+	assert.Len(t, r.Candidates("wooden ring"), 4)
+
+	// A price that nothing could have leaves the candidates alone.
+	assert.NotNil(t, Restrict(r, c, Offer{Item: ring, Price: 7}))
+	assert.Len(t, r.Candidates("wooden ring"), 4)
+
+	// Unknown enchantment could make any cloak cost more.
+	cloak, _ := r.Parse("tattered cape")
+	assert.Nil(t, Restrict(r, c, Offer{Item: cloak, Price: 60}))
+	assert.Len(t, r.Candidates("tattered cape"), 4)
+	cloak.Enhancement = item.Enhancement{Known: true}
+	assert.Nil(t, Restrict(r, c, Offer{Item: cloak, Price: 60}))
+	for _, k := range r.Candidates("tattered cape") {
+		assert.Contains(t, []int{45, 60}, k.Price, k.Name)
+	}
+}